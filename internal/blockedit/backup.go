@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package blockedit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupSuffix separates a file name from its backup timestamp.
+	BackupSuffix = ".arc.bak."
+	// BackupTimeFormat is the layout of backup timestamps.
+	BackupTimeFormat = "20060102-150405"
+)
+
+// Backup copies path to a timestamped sibling (e.g.
+// .bashrc.arc.bak.20250101-120000, with a ".N" counter appended when that
+// second already has a backup) and prunes all but the newest keep backups, logging each pruned file to log (nil discards). It returns the
+// backup path, or "" when keep is zero or less, which disables backups.
+func Backup(path string, keep int, log *slog.Logger) (string, error) {
+	return BackupTo(path, path, keep, log)
+}
+
+// BackupTo is Backup with the backups named after base instead of path, for
+// files whose siblings are picked up by name (e.g. zsh's fpath, which loads
+// every file starting with an underscore).
+func BackupTo(path, base string, keep int, log *slog.Logger) (string, error) {
+	if keep <= 0 {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	backup, err := createBackup(base+BackupSuffix+time.Now().Format(BackupTimeFormat), data, info.Mode().Perm())
+	if err != nil {
+		return "", err
+	}
+
+	if err := pruneBackups(base, keep, Options{Log: log}.logger()); err != nil {
+		return backup, err
+	}
+	return backup, nil
+}
+
+// createBackup writes data to name, or to name.1, name.2, ... when an
+// earlier backup taken in the same second holds the name, and returns the
+// file written. Files are created exclusively so no backup is overwritten.
+func createBackup(name string, data []byte, mode fs.FileMode) (string, error) {
+	for n := 0; ; n++ {
+		backup := name
+		if n > 0 {
+			backup = fmt.Sprintf("%s.%d", name, n)
+		}
+		f, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(backup)
+			return "", err
+		}
+		return backup, nil
+	}
+}
+
+// backupKey is the ordering of one backup: its timestamp, then its
+// same-second counter.
+type backupKey struct {
+	stamp string
+	n     int
+}
+
+// parseBackupName returns the key of a backup file named after prefix
+// (FILE.arc.bak.), or false for any other file.
+func parseBackupName(name, prefix string) (backupKey, bool) {
+	rest, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return backupKey{}, false
+	}
+	stamp, counter, hasCounter := strings.Cut(rest, ".")
+	if _, err := time.Parse(BackupTimeFormat, stamp); err != nil {
+		return backupKey{}, false
+	}
+	key := backupKey{stamp: stamp}
+	if hasCounter {
+		n, err := strconv.Atoi(counter)
+		if err != nil || n <= 0 {
+			return backupKey{}, false
+		}
+		key.n = n
+	}
+	return key, true
+}
+
+// ListBackups returns the arc-created backups of path, oldest first. The
+// directory is read rather than globbed, so a path containing glob
+// metacharacters ([, *, ?) still matches only its own backups.
+func ListBackups(path string) ([]string, error) {
+	dir := filepath.Dir(path)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(path) + BackupSuffix
+	var backups []string
+	keys := make(map[string]backupKey)
+	for _, e := range entries {
+		if key, ok := parseBackupName(e.Name(), prefix); ok && !e.IsDir() {
+			b := filepath.Join(dir, e.Name())
+			backups = append(backups, b)
+			keys[b] = key
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		a, b := keys[backups[i]], keys[backups[j]]
+		if a.stamp != b.stamp {
+			return a.stamp < b.stamp
+		}
+		return a.n < b.n
+	})
+	return backups, nil
+}
+
+// pruneBackups removes all but the newest keep backups of path. Every
+// backup producer goes through Backup, so this is the one place the
+// --keep-backups limit is enforced.
+func pruneBackups(path string, keep int, log *slog.Logger) error {
+	backups, err := ListBackups(path)
+	if err != nil {
+		return err
+	}
+	for len(backups) > keep {
+		err := os.Remove(backups[0])
+		log.Debug("prune backup", "path", backups[0], "keep", keep, "err", err)
+		if err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package blockedit maintains marker-delimited blocks inside user-owned text
+// files such as shell RC files and .gitignore. Every edit is idempotent: a
+// block is added once, and only the text between its markers is ever removed.
+package blockedit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Options controls how Upsert and Remove touch a file.
+type Options struct {
+	// KeepBackups is the number of timestamped backups kept per file. An
+	// existing file is backed up before each change, which is abandoned if
+	// the backup fails; zero skips the backup.
+	KeepBackups int
+	// FollowSymlinks edits the target of a symlinked file instead of
+	// refusing it.
+	FollowSymlinks bool
+	// Replace makes Upsert rewrite an existing block in place when its
+	// content differs from the new block, instead of leaving it alone.
+	Replace bool
+	// DryRun reports what would change without writing.
+	DryRun bool
+	// Log receives debug events; nil discards them.
+	Log *slog.Logger
+}
+
+func (o Options) logger() *slog.Logger {
+	if o.Log != nil {
+		return o.Log
+	}
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// ErrSymlink is matched by the error Resolve returns for a symlink it will
+// not follow.
+var ErrSymlink = errors.New("file is a symlink")
+
+// SymlinkError reports a symlinked file that was not followed.
+type SymlinkError struct {
+	Path, Dest string
+}
+
+func (e *SymlinkError) Error() string {
+	return fmt.Sprintf("%s is a symlink to %s; re-run with --follow-symlinks to edit the target", e.Path, e.Dest)
+}
+
+func (e *SymlinkError) Is(target error) bool { return target == ErrSymlink }
+
+// Resolve returns the file path refers to. A symlink (e.g. into a dotfiles
+// repo) yields a *SymlinkError unless follow is set, in which case the real
+// target is returned so edits and backups land next to it.
+func Resolve(path string, follow bool) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return path, nil
+	}
+	dest, _ := os.Readlink(path)
+	if !follow {
+		return "", &SymlinkError{Path: path, Dest: dest}
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve symlink %s: %w", path, err)
+	}
+	return target, nil
+}
+
+// Find returns the byte offsets of the block delimited by start and end in
+// content, including both markers and the end marker's trailing newline.
+func Find(content, start, end string) (int, int, bool) {
+	i := strings.Index(content, start)
+	if i == -1 {
+		return 0, 0, false
+	}
+	j := strings.Index(content[i:], end)
+	if j == -1 {
+		return 0, 0, false
+	}
+	j += i + len(end)
+	if j < len(content) && content[j] == '\n' {
+		j++
+	}
+	return i, j, true
+}
+
+// Append returns content with block appended, exactly as Upsert writes it.
+func Append(content, block string) string {
+	return content + "\n" + block
+}
+
+// Cut returns content without the block delimited by start and end, exactly
+// as Remove writes it. It reports false when there is no such block.
+//
+// Only the bytes from the start marker through the end marker's newline are
+// dropped, plus the newline Append puts before a block: the blank line it
+// leaves, or, for a block at the end of the file, the newline it adds after
+// a last line that had none. Removing an appended block thus restores the
+// file byte for byte. The markers are ASCII,
+// and in UTF-8 ASCII bytes never occur inside a multibyte character, so the
+// offsets Find returns always fall on character boundaries and the text
+// around the block is never trimmed or split, whatever its encoding.
+func Cut(content, start, end string) (string, bool) {
+	i, j, ok := Find(content, start, end)
+	if !ok {
+		return content, false
+	}
+	before := content[:i]
+	if before == "\n" || strings.HasSuffix(before, "\n\n") || (j == len(content) && strings.HasSuffix(before, "\n")) {
+		before = before[:len(before)-1]
+	}
+	return before + content[j:], true
+}
+
+// Splice returns content with the block delimited by start and end replaced
+// by block, keeping the lines around it in place. It reports false when
+// there is no such block or it already matches block.
+func Splice(content, start, end, block string) (string, bool) {
+	i, j, ok := Find(content, start, end)
+	if !ok || strings.TrimSuffix(content[i:j], "\n") == strings.TrimSuffix(block, "\n") {
+		return content, false
+	}
+	return content[:i] + block + content[j:], true
+}
+
+// Upsert appends block, which must include its start and end markers, to
+// path unless a block delimited by those markers is already there. With
+// Replace, a differing existing block is rewritten in place instead. The
+// file is created if needed. It reports whether the file changed (or would,
+// with DryRun).
+func Upsert(path, start, end, block string, opts Options) (bool, error) {
+	log := opts.logger()
+	path, err := Resolve(path, opts.FollowSymlinks)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(path)
+	log.Debug("read managed file", "path", path, "err", err)
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	if _, _, ok := Find(string(data), start, end); ok {
+		if !opts.Replace {
+			return false, nil
+		}
+		updated, changed := Splice(string(data), start, end, block)
+		if !changed || opts.DryRun {
+			return changed, nil
+		}
+		if opts.KeepBackups > 0 {
+			backup, err := Backup(path, opts.KeepBackups, log)
+			log.Debug("backup managed file", "path", path, "backup", backup, "err", err)
+			if err != nil {
+				return false, fmt.Errorf("failed to back up %s: %w", path, err)
+			}
+		}
+		err := os.WriteFile(path, []byte(updated), 0o644)
+		log.Debug("replace managed block", "path", path, "err", err)
+		return err == nil, err
+	}
+	if opts.DryRun {
+		return true, nil
+	}
+
+	if exists && opts.KeepBackups > 0 {
+		backup, err := Backup(path, opts.KeepBackups, log)
+		log.Debug("backup managed file", "path", path, "backup", backup, "err", err)
+		if err != nil {
+			return false, fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	log.Debug("open managed file for append", "path", path, "err", err)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	_, err = f.WriteString("\n" + block)
+	log.Debug("append managed block", "path", path, "err", err)
+	return err == nil, err
+}
+
+// Remove deletes the block delimited by start and end from path. A file
+// without the block is left untouched; a missing file is an error. It
+// reports whether the file changed (or would, with DryRun).
+func Remove(path, start, end string, opts Options) (bool, error) {
+	log := opts.logger()
+	path, err := Resolve(path, opts.FollowSymlinks)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(path)
+	log.Debug("read managed file", "path", path, "err", err)
+	if err != nil {
+		return false, err
+	}
+	updated, ok := Cut(string(data), start, end)
+	if !ok {
+		log.Debug("no managed block to remove", "path", path)
+		return false, nil
+	}
+	if opts.DryRun {
+		return true, nil
+	}
+
+	if opts.KeepBackups > 0 {
+		backup, err := Backup(path, opts.KeepBackups, log)
+		log.Debug("backup managed file", "path", path, "backup", backup, "err", err)
+		if err != nil {
+			return false, fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	err = os.WriteFile(path, []byte(updated), 0o644)
+	log.Debug("write managed file", "path", path, "err", err)
+	return err == nil, err
+}
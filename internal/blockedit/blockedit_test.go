@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package blockedit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testStart = "# >>> test >>>"
+	testEnd   = "# <<< test <<<"
+	testBlock = testStart + "\nsource completions\n" + testEnd + "\n"
+)
+
+func TestUpsertSymlink(t *testing.T) {
+	original := "export PATH=$HOME/bin:$PATH\n"
+	setup := func(t *testing.T) (link, target string) {
+		dotfiles, home := t.TempDir(), t.TempDir()
+		target = filepath.Join(dotfiles, "bashrc")
+		link = filepath.Join(home, ".bashrc")
+		if err := os.WriteFile(target, []byte(original), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+		return link, target
+	}
+
+	t.Run("refused", func(t *testing.T) {
+		link, target := setup(t)
+		_, err := Upsert(link, testStart, testEnd, testBlock, Options{KeepBackups: 1})
+		if !errors.Is(err, ErrSymlink) {
+			t.Fatalf("Upsert error = %v, want ErrSymlink", err)
+		}
+		if got, _ := os.ReadFile(target); string(got) != original {
+			t.Errorf("target changed to %q", got)
+		}
+		if backups, _ := ListBackups(target); len(backups) != 0 {
+			t.Errorf("backups written: %v", backups)
+		}
+	})
+
+	t.Run("followed", func(t *testing.T) {
+		link, target := setup(t)
+		changed, err := Upsert(link, testStart, testEnd, testBlock, Options{KeepBackups: 1, FollowSymlinks: true})
+		if err != nil || !changed {
+			t.Fatalf("Upsert = %v, %v; want true, nil", changed, err)
+		}
+		if got, _ := os.ReadFile(target); !strings.Contains(string(got), testBlock) {
+			t.Errorf("target lacks the block:\n%s", got)
+		}
+		if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("link replaced by a regular file (err %v)", err)
+		}
+		if backups, _ := ListBackups(target); len(backups) != 1 {
+			t.Errorf("backups next to target = %v, want one", backups)
+		}
+		if backups, _ := ListBackups(link); len(backups) != 0 {
+			t.Errorf("backups next to link = %v, want none", backups)
+		}
+	})
+}
+
+func TestSplice(t *testing.T) {
+	stale := testStart + "\nsource old-completions\n" + testEnd + "\n"
+	tests := []struct {
+		name        string
+		content     string
+		want        string
+		wantChanged bool
+	}{
+		{"between lines", "alias ll='ls -l'\nexport A=1\n" + stale + "export B=2\nalias g=git\n",
+			"alias ll='ls -l'\nexport A=1\n" + testBlock + "export B=2\nalias g=git\n", true},
+		{"at end", "export A=1\n\n" + stale, "export A=1\n\n" + testBlock, true},
+		{"end marker without newline", "export A=1\n" + strings.TrimSuffix(stale, "\n"), "export A=1\n" + testBlock, true},
+		{"current", "export A=1\n" + testBlock + "export B=2\n", "export A=1\n" + testBlock + "export B=2\n", false},
+		{"no block", "export A=1\n", "export A=1\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := Splice(tt.content, testStart, testEnd, testBlock)
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("Splice = %q, %v; want %q, %v", got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestAppendCutRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"empty", ""},
+		{"ascii", "export A=1\n"},
+		{"no trailing newline", "export A=1"},
+		{"trailing blank line", "export A=1\n\n"},
+		{"cjk", "# 設定ファイル\nalias 日本=ls\n"},
+		{"ideographic space", "export GREETING=こんにちは　"},
+		{"emoji", "PS1='🚀 $ '\n"},
+		{"combining marks", "# café naïve\n"},
+		{"accented without newline", "alias ñ=ls"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appended := Append(tt.content, testBlock)
+			got, ok := Cut(appended, testStart, testEnd)
+			if !ok {
+				t.Fatal("Cut found no block")
+			}
+			if got != tt.content {
+				t.Errorf("round trip = %q, want %q", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestCutKeepsMultibyteNeighbors(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+	}{
+		{"cjk", "# 設定\n", "# 終わり\n"},
+		{"ideographic spaces", "x=1　\n", "　y=2\n"},
+		{"emoji", "🐚\n", "🐢\n"},
+		{"no newline after", "ü\n", "ß"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Cut(tt.before+testBlock+tt.after, testStart, testEnd)
+			if !ok || got != tt.before+tt.after {
+				t.Errorf("Cut = %q, %v; want %q", got, ok, tt.before+tt.after)
+			}
+		})
+	}
+}
+
+func TestBackupNamesAndListing(t *testing.T) {
+	// The brackets and star would be glob metacharacters if the directory
+	// were globbed; the sibling file must not be listed as a backup.
+	dir := filepath.Join(t.TempDir(), "dot[files]*")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, ".bashrc")
+	if err := os.WriteFile(path+".old"+BackupSuffix+"20250101-120000", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var made []string
+	for i := range 12 {
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		backup, err := Backup(path, 20, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		made = append(made, backup)
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(backups, "\n") != strings.Join(made, "\n") {
+		t.Fatalf("ListBackups =\n%s\nwant, oldest first,\n%s", strings.Join(backups, "\n"), strings.Join(made, "\n"))
+	}
+	for i, b := range backups {
+		if data, _ := os.ReadFile(b); len(data) != i {
+			t.Errorf("backup %s = %q, want %d bytes: an earlier backup was overwritten", b, data, i)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package blockedit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+type diffOp struct {
+	kind byte // ' ', '-', or '+'
+	line string
+}
+
+// Diff returns a unified diff from old to new, labelled with name, or ""
+// when they are equal. It is meant for the small files blockedit edits: the
+// common prefix and suffix are trimmed and the rest is compared with a plain
+// LCS table.
+func Diff(name, old, new string) string {
+	if old == new {
+		return ""
+	}
+	ops := diffLines(splitLines(old), splitLines(new))
+
+	// consumedOld[k] and consumedNew[k] count the lines of each side used
+	// by ops[:k], which gives every hunk its line numbers.
+	consumedOld := make([]int, len(ops)+1)
+	consumedNew := make([]int, len(ops)+1)
+	for k, op := range ops {
+		consumedOld[k+1], consumedNew[k+1] = consumedOld[k], consumedNew[k]
+		if op.kind != '+' {
+			consumedOld[k+1]++
+		}
+		if op.kind != '-' {
+			consumedNew[k+1]++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", name, name)
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		start := max(i-diffContext, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				end = min(end+diffContext, len(ops))
+				break
+			}
+			end = run
+		}
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(consumedOld[start], consumedOld[end]-consumedOld[start]),
+			hunkRange(consumedNew[start], consumedNew[end]-consumedNew[start]))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			b.WriteByte('\n')
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange formats a hunk's start,length pair; skipped lines precede it.
+func hunkRange(skipped, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", skipped)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", skipped+1)
+	}
+	return fmt.Sprintf("%d,%d", skipped+1, length)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the edit script turning x into y.
+func diffLines(x, y []string) []diffOp {
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range x[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	mx, my := x[prefix:len(x)-suffix], y[prefix:len(y)-suffix]
+	lcs := make([][]int, len(mx)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(my)+1)
+	}
+	for i := len(mx) - 1; i >= 0; i-- {
+		for j := len(my) - 1; j >= 0; j-- {
+			if mx[i] == my[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(mx) && j < len(my) {
+		switch {
+		case mx[i] == my[j]:
+			ops = append(ops, diffOp{' ', mx[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', mx[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', my[j]})
+			j++
+		}
+	}
+	for ; i < len(mx); i++ {
+		ops = append(ops, diffOp{'-', mx[i]})
+	}
+	for ; j < len(my); j++ {
+		ops = append(ops, diffOp{'+', my[j]})
+	}
+
+	for _, line := range x[len(x)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// aliasNamePattern matches the alias names --alias accepts: plain command
+// words that are safe to embed unquoted in every shell's script.
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.+-]*$`)
+
+func validateAliases(aliases []string, command string) error {
+	for _, a := range aliases {
+		if !aliasNamePattern.MatchString(a) {
+			return fmt.Errorf("invalid --alias %q: use letters, digits, '.', '_', '+', or '-'", a)
+		}
+		if a == command {
+			return fmt.Errorf("invalid --alias %q: that is the command itself", a)
+		}
+	}
+	return nil
+}
+
+// withAliases adds registrations to a generated completion script so that
+// each alias completes like command. zsh lists the aliases on its #compdef
+// line; bash, PowerShell, elvish, and xonsh reuse the script's completer, and
+// tcsh repeats its complete rule for each alias. Fish needs
+// one wrapper file per alias instead (see fishAliasFiles), and nushell
+// completes aliases of externs on its own, so both are returned unchanged.
+func withAliases(shell string, script []byte, command string, aliases []string) []byte {
+	if len(aliases) == 0 {
+		return script
+	}
+
+	var extra strings.Builder
+	switch shell {
+	case "zsh":
+		first := []byte("#compdef " + command)
+		if bytes.HasPrefix(script, first) {
+			return append([]byte("#compdef "+command+" "+strings.Join(aliases, " ")), script[len(first):]...)
+		}
+		return script
+	case "bash":
+		fn := "__start_" + command
+		for _, a := range aliases {
+			fmt.Fprintf(&extra, "complete -o default -F %s %s\n", fn, a)
+		}
+	case "powershell":
+		block := "${__" + strings.ReplaceAll(command, "-", "_") + "CompleterBlock}"
+		for _, a := range aliases {
+			fmt.Fprintf(&extra, "Register-ArgumentCompleter -CommandName '%s' -ScriptBlock %s\n", a, block)
+		}
+	case "elvish":
+		for _, a := range aliases {
+			fmt.Fprintf(&extra, "set edit:completion:arg-completer[%s] = $edit:completion:arg-completer[%s]\n", a, command)
+		}
+	case "xonsh":
+		for _, a := range aliases {
+			fmt.Fprintf(&extra, "_arc_commands.add(%q)\n", a)
+		}
+	case "tcsh":
+		for _, a := range aliases {
+			fmt.Fprintln(&extra, tcshCompleteLine(a, command))
+		}
+	default:
+		return script
+	}
+
+	out := append([]byte(nil), script...)
+	if len(out) > 0 && out[len(out)-1] != '\n' {
+		out = append(out, '\n')
+	}
+	out = append(out, "\n# Aliases registered by arc-init shell --alias\n"...)
+	return append(out, extra.String()...)
+}
+
+// fishAliasFiles returns the wrapper files fish needs for aliases, keyed by
+// path. Fish loads completions by command name, so each alias gets its own
+// file next to the main one.
+func fishAliasFiles(dir, command string, aliases []string) map[string][]byte {
+	files := make(map[string][]byte, len(aliases))
+	for _, a := range aliases {
+		files[filepath.Join(dir, a+".fish")] = []byte(fmt.Sprintf(
+			"# Generated by arc-init shell --alias. Do not edit manually.\ncomplete -c %s -w %s\n", a, command))
+	}
+	return files
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-init/internal/blockedit"
+)
+
+const defaultKeepBackups = 3
+
+// restoreBackup copies the backup of path matching selector over path and
+// returns the backup used, with a diff of the change. An empty or "latest"
+// selector picks the newest backup; otherwise selector must be a backup path
+// or timestamp. The contents being replaced are backed up first and the
+// file keeps its mode; a dry run only computes the diff.
+func restoreBackup(path, selector string, opts shellOptions) (string, string, error) {
+	backups, err := blockedit.ListBackups(path)
+	if err != nil {
+		return "", "", err
+	}
+	if len(backups) == 0 {
+		return "", "", fmt.Errorf("no backups found for %s", path)
+	}
+
+	chosen := ""
+	if selector == "" || selector == "latest" {
+		chosen = backups[len(backups)-1]
+	} else {
+		for _, b := range backups {
+			if b == selector || strings.HasSuffix(b, blockedit.BackupSuffix+selector) {
+				chosen = b
+				break
+			}
+		}
+		if chosen == "" {
+			return "", "", fmt.Errorf("backup %q not found for %s", selector, path)
+		}
+	}
+
+	data, err := os.ReadFile(chosen)
+	if err != nil {
+		return "", "", err
+	}
+	mode := fs.FileMode(0o644)
+	var current []byte
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		if current, err = os.ReadFile(path); err != nil {
+			return "", "", err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", "", err
+	}
+	diff := blockedit.Diff(path, string(current), string(data))
+	if opts.dryRun || diff == "" {
+		return chosen, diff, nil
+	}
+
+	if err := opts.rcTxn.snapshot(path); err != nil {
+		return "", "", err
+	}
+	if current != nil {
+		if _, err := blockedit.Backup(path, opts.keepBackups, opts.logger()); err != nil {
+			return "", "", fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return "", "", err
+	}
+	return chosen, diff, nil
+}
+
+// restoreShellRC lists the RC backups for each shell and restores the one
+// matching --restore to the RC file the other flags select. A dry run
+// prints the diff instead.
+func restoreShellRC(cmd *cobra.Command, shells []string, opts shellOptions) error {
+	out := cmd.OutOrStdout()
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== RC Backups ===")
+	fmt.Fprintln(out)
+
+	var failed []string
+	for _, sh := range shells {
+		rc := opts.rcPathFor(sh)
+		if rc == "" {
+			continue
+		}
+		// Backups of a symlinked RC file are kept next to its target.
+		rc, err := opts.rcTarget(rc)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "restore %s RC: %v\n", sh, err)
+			failed = append(failed, sh)
+			continue
+		}
+
+		fmt.Fprintf(out, "%s: %s\n", strings.ToUpper(sh), rc)
+		backups, err := blockedit.ListBackups(rc)
+		if err != nil {
+			return err
+		}
+		if len(backups) == 0 {
+			fmt.Fprintln(out, "  No backups available")
+			fmt.Fprintln(out)
+			continue
+		}
+		for _, b := range backups {
+			fmt.Fprintf(out, "  - %s\n", filepath.Base(b))
+		}
+
+		restored, diff, err := restoreBackup(rc, opts.restore, opts)
+		switch {
+		case err != nil:
+			fmt.Fprintf(cmd.ErrOrStderr(), "restore %s RC: %v\n", sh, err)
+			failed = append(failed, sh)
+		case diff == "":
+			fmt.Fprintf(out, "  UNCHANGED: already matches %s\n", filepath.Base(restored))
+		case opts.dryRun:
+			fmt.Fprintf(out, "  Would restore from %s (dry-run)\n", filepath.Base(restored))
+			fmt.Fprint(out, diff)
+		default:
+			fmt.Fprintf(out, "  RESTORED from %s\n", filepath.Base(restored))
+		}
+		fmt.Fprintln(out)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to restore RC for: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// cleanRCBackups deletes the backups arc-init made of the RC file at
+// s.rcPath, for --clean-backups after its block was removed, and records
+// them in s. Only blockedit's own naming (FILE.arc.bak.TIMESTAMP) matches,
+// so copies the user made are never touched. A dry run only lists them.
+func (s *shellStatus) cleanRCBackups(stderr io.Writer, opts shellOptions) {
+	backups, err := blockedit.ListBackups(s.rcPath)
+	if err != nil {
+		s.addError(stderr, fmt.Errorf("list %s RC backups: %w", s.shell, err))
+		return
+	}
+	for _, b := range backups {
+		if !opts.dryRun {
+			err := os.Remove(b)
+			opts.logger().Debug("remove RC backup", "path", b, "err", err)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				s.addError(stderr, fmt.Errorf("remove %s RC backup: %w", s.shell, err))
+				continue
+			}
+		}
+		s.backupsRemoved = append(s.backupsRemoved, b)
+	}
+}
+
+// backupCompletion saves a copy of the completion file at path before it is
+// overwritten and returns the backup path, or "" when there was nothing to
+// back up or keep is zero. Backups are dot-prefixed so that bash-completion,
+// zsh's compinit, and fish never load them as completions.
+func backupCompletion(path string, keep int, log *slog.Logger) (string, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return blockedit.BackupTo(path, filepath.Join(filepath.Dir(path), "."+filepath.Base(path)), keep, log)
+}
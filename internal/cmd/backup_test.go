@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-init/internal/blockedit"
+)
+
+func TestRestoreBackupHonorsDryRunAndMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".bashrc")
+	if err := os.WriteFile(path, []byte("original\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := blockedit.Backup(path, defaultKeepBackups, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("edited\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := shellOptions{dryRun: true, keepBackups: defaultKeepBackups}
+	_, diff, err := restoreBackup(path, "latest", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "-edited") || !strings.Contains(diff, "+original") {
+		t.Errorf("dry-run diff:\n%s", diff)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "edited\n" {
+		t.Fatalf("dry run wrote %s: %q", path, data)
+	}
+
+	opts.dryRun = false
+	if _, _, err := restoreBackup(path, "latest", opts); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "original\n" {
+		t.Errorf("restored %s = %q", path, data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("mode after restore = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+	backups, err := blockedit.ListBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(backups[len(backups)-1])
+	if string(data) != "edited\n" {
+		t.Errorf("replaced contents not backed up; newest backup = %q", data)
+	}
+}
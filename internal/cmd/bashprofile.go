@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourorg/arc-init/internal/blockedit"
+)
+
+// bashProfileBlock makes login shells, which macOS Terminal opens by default,
+// load ~/.bashrc.
+const bashProfileBlock = rcStart + "\n" + `# Load ~/.bashrc in login shells (arc completions live there)
+if [ -f "$HOME/.bashrc" ]; then
+  . "$HOME/.bashrc"
+fi` + "\n" + rcEnd + "\n"
+
+// ensureBashProfileSourcesBashrc adds bashProfileBlock to the file bash login
+// shells read (~/.bash_profile, ~/.bash_login, or ~/.profile) when the bash
+// RC block went into ~/.bashrc and that file does not source it yet. Only
+// macOS needs this; elsewhere terminals start non-login shells.
+func ensureBashProfileSourcesBashrc(status *shellStatus, opts shellOptions) error {
+	if opts.rcFile != "" || filepath.Base(status.rcPath) != ".bashrc" {
+		return nil
+	}
+
+	profile, err := opts.rcTarget(opts.paths.loginRCPathFor("bash"))
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(profile)
+	opts.logger().Debug("read bash profile", "path", profile, "err", err)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	content := string(data)
+	if sourcesBashrc(content) {
+		return nil
+	}
+
+	// A completion block left in the profile by an earlier install uses the
+	// same markers, so it is replaced: with the profile loading ~/.bashrc,
+	// login shells get completions from the block written there.
+	action := "make login shells load ~/.bashrc by adding to"
+	updated, replaced := blockedit.Splice(content, rcStart, rcEnd, bashProfileBlock)
+	if replaced {
+		action = "make login shells load ~/.bashrc by replacing the arc block in"
+	} else {
+		updated = blockedit.Append(content, bashProfileBlock)
+	}
+	if !opts.dryRun && !opts.confirm.confirm(action, profile, blockedit.Diff(profile, content, updated)) {
+		return nil
+	}
+	status.profilePath = profile
+	if opts.dryRun {
+		return nil
+	}
+	return opts.rcTxn.upsert(profile, bashProfileBlock, true, opts.keepBackups, opts.logger())
+}
+
+// sourceBashrcCommand matches a `.` or `source` of a .bashrc file at the
+// start of a command, e.g. `[ -f ~/.bashrc ] && . ~/.bashrc` or
+// `then source "$HOME/.bashrc"`.
+var sourceBashrcCommand = regexp.MustCompile(`(?:^|[;&|{(]|\bthen|\bdo|\belse)[ \t]*(?:\.|source)[ \t]+\S*/\.bashrc(?:["'\s;&|)}]|$)`)
+
+// sourcesBashrc reports whether a profile already loads ~/.bashrc, through
+// bashProfileBlock or a line of the user's own. Lines that only mention it,
+// such as comments or an `echo`, do not count, and neither does anything
+// inside another arc block.
+func sourcesBashrc(content string) bool {
+	if i, j, ok := blockedit.Find(content, rcStart, rcEnd); ok {
+		if content[i:j] == bashProfileBlock {
+			return true
+		}
+		content, _ = blockedit.Cut(content, rcStart, rcEnd)
+	}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if sourceBashrcCommand.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBashProfile reports whether path is the login profile
+// ensureBashProfileSourcesBashrc edits rather than the bash RC file itself.
+func isBashProfile(path string, opts shellOptions) bool {
+	return opts.rcFile == "" && path == opts.paths.loginRCPathFor("bash") && path != opts.rcPathFor("bash")
+}
+
+// removeBashProfileBlock deletes bashProfileBlock from the login profile at
+// path on uninstall, recording the removal on status. A missing profile, or
+// one without the block, is left alone.
+func removeBashProfileBlock(status *shellStatus, path string, opts shellOptions) error {
+	profile, err := opts.rcTarget(path)
+	if err != nil {
+		return err
+	}
+	if diff := rcRemovalDiff(profile); diff != "" && !opts.dryRun && !opts.confirm.confirm("remove the arc block from", profile, diff) {
+		return nil
+	}
+	removed, err := opts.rcTxn.remove(profile, opts.dryRun, opts.keepBackups, opts.logger())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if removed {
+		status.profilePath = profile
+		status.profileRemoved = true
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSourcesBashrc(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{". ~/.bashrc\n", true},
+		{"source \"$HOME/.bashrc\"\n", true},
+		{"[ -f ~/.bashrc ] && . ~/.bashrc\n", true},
+		{"if [ -f ~/.bashrc ]; then . ~/.bashrc; fi\n", true},
+		{"if [ -f \"$HOME/.bashrc\" ]; then\n  source \"$HOME/.bashrc\"\nfi\n", true},
+		{bashProfileBlock, true},
+		{"export A=1\n\n" + bashProfileBlock, true},
+		{rcStart + "\nsource ~/.config/bash/completions/arc.bash\n" + rcEnd + "\n", false},
+		{rcStart + "\n. ~/.bashrc\n" + rcEnd + "\n", false},
+		{". ~/.bashrc\n\n" + rcStart + "\nsource ~/.config/bash/completions/arc.bash\n" + rcEnd + "\n", true},
+		{"", false},
+		{"# . ~/.bashrc\n", false},
+		{"echo \"edit ~/.bashrc to customize\"\n", false},
+		{"cp ~/.bashrc ~/.bashrc.orig\n", false},
+		{". ~/.bashrc.local\n", false},
+	}
+	for _, tt := range tests {
+		if got := sourcesBashrc(tt.content); got != tt.want {
+			t.Errorf("sourcesBashrc(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestBashProfileSourcesBashrcOnlyOnMacOS(t *testing.T) {
+	for _, goos := range []string{"darwin", "linux"} {
+		t.Run(goos, func(t *testing.T) {
+			home := t.TempDir()
+			profile := filepath.Join(home, ".profile")
+			original := "export EDITOR=vi\n"
+			if err := os.WriteFile(filepath.Join(home, ".bashrc"), nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(profile, []byte(original), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			opts := shellOptions{
+				writeRC: true,
+				paths:   pathContext{goos: goos, home: home, configHome: filepath.Join(home, ".config"), zdotdir: home},
+			}
+
+			status := installShell(io.Discard, NewRootCmd(), "bash", opts)
+			if len(status.errs) > 0 {
+				t.Fatalf("install: %v", status.errs)
+			}
+			data, err := os.ReadFile(profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if goos != "darwin" {
+				if string(data) != original || status.profilePath != "" {
+					t.Fatalf("profile edited on %s:\n%s", goos, data)
+				}
+				return
+			}
+			if !strings.Contains(string(data), bashProfileBlock) || status.profilePath != profile {
+				t.Fatalf("profilePath = %q, profile:\n%s", status.profilePath, data)
+			}
+			if again := installShell(io.Discard, NewRootCmd(), "bash", opts); again.profilePath != "" {
+				t.Errorf("second install edited the profile again: %q", again.profilePath)
+			}
+			if _, err := os.Stat(filepath.Join(home, ".bash_profile")); err == nil {
+				t.Error(".bash_profile created, shadowing .profile")
+			}
+
+			opts.writeRC, opts.uninstallRC = false, true
+			status = installShell(io.Discard, NewRootCmd(), "bash", opts)
+			if len(status.errs) > 0 {
+				t.Fatalf("uninstall: %v", status.errs)
+			}
+			if data, _ := os.ReadFile(profile); string(data) != original || !status.profileRemoved {
+				t.Errorf("profileRemoved = %v, profile after uninstall:\n%s", status.profileRemoved, data)
+			}
+		})
+	}
+}
+
+func TestBashProfileCompletionBlockIsReplaced(t *testing.T) {
+	home := t.TempDir()
+	profile := filepath.Join(home, ".bash_profile")
+	opts := shellOptions{
+		writeRC: true,
+		paths:   pathContext{goos: "darwin", home: home, configHome: filepath.Join(home, ".config"), zdotdir: home},
+	}
+	_, block, err := rcBlockFor("bash", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An earlier install, made before ~/.bashrc existed, left the completion
+	// block in the profile.
+	if err := os.WriteFile(profile, []byte("export EDITOR=vi\n\n"+block), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".bashrc"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	status := installShell(io.Discard, NewRootCmd(), "bash", opts)
+	if len(status.errs) > 0 {
+		t.Fatalf("install: %v", status.errs)
+	}
+	data, err := os.ReadFile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "export EDITOR=vi\n\n" + bashProfileBlock; string(data) != want || status.profilePath != profile {
+		t.Errorf("profilePath = %q, profile:\n%s\nwant:\n%s", status.profilePath, data, want)
+	}
+}
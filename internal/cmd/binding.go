@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Lines through which bash, zsh, and fish scripts register the commands they
+// complete. The captured field is the command name.
+var (
+	bashCompleteLine   = regexp.MustCompile(`(?m)^[ \t]*complete[ \t].*[ \t](\S+)[ \t]*$`)
+	zshCompdefLine     = regexp.MustCompile(`(?m)^[ \t]*#?compdef[ \t]+(?:_\S+[ \t]+)?(\S+)`)
+	fishCompleteLine   = regexp.MustCompile(`(?m)^[ \t]*complete[ \t]+(?:--command|-c)[ \t]+(\S+)`)
+	bindingLinePattern = map[string]*regexp.Regexp{
+		"bash": bashCompleteLine,
+		"zsh":  zshCompdefLine,
+		"fish": fishCompleteLine,
+	}
+)
+
+// registeredCommands returns the command names script registers completions
+// for, or nil for shells whose registration is not parsed.
+func registeredCommands(shell string, script []byte) []string {
+	re, ok := bindingLinePattern[shell]
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, m := range re.FindAllSubmatch(script, -1) {
+		name := strings.Trim(string(m[1]), `"'`)
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// checkCommandBinding returns a warning when script would not fire for
+// command: when it registers other commands only, or when command is not on
+// PATH, so the user typing it never reaches the completion. It returns ""
+// when the binding looks right.
+func checkCommandBinding(shell string, script []byte, command string) string {
+	if names := registeredCommands(shell, script); len(names) > 0 && !slices.Contains(names, command) {
+		return fmt.Sprintf("script completes %s, not %s; pass --command-name %s", strings.Join(names, ", "), command, command)
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Sprintf("%s is not on PATH, so the completion cannot fire; use --command-name if arc is installed under another name", command)
+	}
+	return ""
+}
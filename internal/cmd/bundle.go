@@ -0,0 +1,324 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	bundleManifestName = "manifest.json"
+	bundleScriptName   = "install.sh"
+	// bundleMaxFileSize bounds each archive member read by --install-bundle.
+	bundleMaxFileSize = 8 << 20
+)
+
+// bundleManifest lists the files in a --bundle archive and where each one is
+// installed. Targets under the generating user's home are stored as
+// $HOME/..., so the bundle installs into whichever home unpacks it.
+type bundleManifest struct {
+	Version   string       `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	Files     []bundleFile `json:"files"`
+}
+
+type bundleFile struct {
+	Shell  string `json:"shell"`
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	// Alias marks the fish wrapper files written for --alias.
+	Alias bool `json:"alias,omitempty"`
+}
+
+var bundleInstallTemplate = template.Must(template.New("install").Parse(`#!/bin/sh
+# Installs the arc-init completions in this bundle.
+# Generated by arc-init shell --bundle (arc-init {{.Version}}).
+# Existing files are kept unless FORCE=1 is set.
+set -eu
+cd "$(dirname "$0")"
+
+install_file() {
+  if [ -e "$2" ] && [ "${FORCE:-0}" != 1 ]; then
+    echo "skip $2 (exists; set FORCE=1 to overwrite)"
+    return
+  fi
+  mkdir -p "$(dirname "$2")"
+  cp "$1" "$2"
+  echo "installed $2"
+}
+{{range .Files}}
+install_file "{{.Name}}" "{{.Target}}"{{end}}
+`))
+
+// writeBundle packs the completion files for shells into a tar.gz at dest,
+// together with manifest.json and install.sh, without installing anything.
+func writeBundle(cmd *cobra.Command, dest string, shells []string, opts shellOptions) error {
+	root := cmd.Root()
+	manifest := bundleManifest{Version: version, CreatedAt: time.Now().UTC()}
+	contents := make(map[string][]byte)
+
+	add := func(shell, target string, data []byte, alias bool) {
+		name := path.Join("completions", shell, filepath.Base(target))
+		manifest.Files = append(manifest.Files, bundleFile{Shell: shell, Name: name, Target: opts.paths.homeRelative(target), Alias: alias})
+		contents[name] = data
+	}
+	for _, sh := range shells {
+		target, err := completionPath(sh, opts)
+		if err != nil {
+			return err
+		}
+		script, err := completionScript(root, sh, target, opts)
+		if err != nil {
+			return fmt.Errorf("%s completion: %w", sh, err)
+		}
+		add(sh, target, script, false)
+		if sh == "fish" {
+			aliasFiles := fishAliasFiles(filepath.Dir(target), opts.command(), opts.aliases)
+			paths := make([]string, 0, len(aliasFiles))
+			for p := range aliasFiles {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			for _, p := range paths {
+				add(sh, p, aliasFiles[p], true)
+			}
+		}
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	var script bytes.Buffer
+	if err := bundleInstallTemplate.Execute(&script, manifest); err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Would write bundle %s (dry-run):\n", dest)
+	} else if err := writeBundleArchive(dest, manifest, manifestData, script.Bytes(), contents); err != nil {
+		return fmt.Errorf("failed to write bundle %s: %w", dest, err)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote bundle %s:\n", dest)
+	}
+	for _, f := range manifest.Files {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s -> %s\n", f.Name, f.Target)
+	}
+	return nil
+}
+
+func writeBundleArchive(dest string, manifest bundleManifest, manifestData, script []byte, contents map[string][]byte) (err error) {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	put := func(name string, data []byte, mode int64) error {
+		hdr := &tar.Header{Name: name, Mode: mode, Size: int64(len(data)), ModTime: manifest.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := put(bundleManifestName, append(manifestData, '\n'), 0o644); err != nil {
+		return err
+	}
+	if err := put(bundleScriptName, script, 0o755); err != nil {
+		return err
+	}
+	for _, file := range manifest.Files {
+		if err := put(file.Name, contents[file.Name], 0o644); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readBundle loads the manifest and listed files from a --bundle archive.
+// Members the manifest does not list are ignored.
+func readBundle(src string) (bundleManifest, map[string][]byte, error) {
+	var manifest bundleManifest
+	f, err := os.Open(src)
+	if err != nil {
+		return manifest, nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("%s is not a gzip archive: %w", src, err)
+	}
+	tr := tar.NewReader(gz)
+	members := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return manifest, nil, fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > bundleMaxFileSize {
+			return manifest, nil, fmt.Errorf("%s: %s is too large for a completion file", src, hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return manifest, nil, fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		members[hdr.Name] = data
+	}
+
+	data, ok := members[bundleManifestName]
+	if !ok {
+		return manifest, nil, fmt.Errorf("%s has no %s; was it created by arc-init shell --bundle?", src, bundleManifestName)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, nil, fmt.Errorf("failed to parse %s in %s: %w", bundleManifestName, src, err)
+	}
+	for _, file := range manifest.Files {
+		if _, ok := members[file.Name]; !ok {
+			return manifest, nil, fmt.Errorf("%s: manifest lists %s but the archive does not contain it", src, file.Name)
+		}
+	}
+	return manifest, members, nil
+}
+
+// bundleTarget expands the target of file against the local home directory
+// and checks that it is a place this install would put the shell's
+// completions: the completion path itself or, for fish alias wrappers, a file
+// beside it. Anything else, such as $HOME/.ssh/authorized_keys in a tampered
+// manifest, is refused.
+func bundleTarget(file bundleFile, opts shellOptions) (string, error) {
+	target := file.Target
+	if rest, ok := strings.CutPrefix(target, "$HOME/"); ok {
+		if opts.paths.home == "" {
+			return "", fmt.Errorf("cannot place %s: home directory unknown", target)
+		}
+		target = filepath.Join(opts.paths.home, filepath.FromSlash(rest))
+	}
+	if !filepath.IsAbs(target) || filepath.Clean(target) != target {
+		return "", fmt.Errorf("refusing bundle target %q: not a clean absolute path", target)
+	}
+
+	want, err := completionPath(file.Shell, opts)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case !file.Alias && target == want:
+	case file.Alias && file.Shell == "fish" && filepath.Dir(target) == filepath.Dir(want) && strings.HasSuffix(target, ".fish"):
+	default:
+		return "", fmt.Errorf("refusing bundle target %s: not where %s completions are installed (%s); pass the location flags the bundle was made with", target, file.Shell, want)
+	}
+	return target, nil
+}
+
+// installBundle places the files of a --bundle archive at their recorded
+// targets. Existing files are kept unless --force is set, and files that
+// already hold the bundled content are never rewritten.
+func installBundle(cmd *cobra.Command, src string, opts shellOptions) ([]shellStatus, error) {
+	manifest, members, err := readBundle(src)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Version != version {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: bundle was generated by arc-init %s, this is %s\n", manifest.Version, version)
+	}
+
+	byShell := make(map[string]*shellStatus)
+	var order []string
+	for _, file := range manifest.Files {
+		s, ok := byShell[file.Shell]
+		if !ok {
+			s = &shellStatus{shell: file.Shell, dryRun: opts.dryRun}
+			byShell[file.Shell] = s
+			order = append(order, file.Shell)
+		}
+
+		target, err := bundleTarget(file, opts)
+		if err != nil {
+			s.addError(cmd.ErrOrStderr(), fmt.Errorf("%s completion: %w", file.Shell, err))
+			continue
+		}
+		isAlias := file.Alias
+		if isAlias {
+			s.aliasPaths = append(s.aliasPaths, target)
+		} else {
+			s.path = target
+		}
+
+		if _, err := os.Stat(target); err == nil && !opts.force {
+			if !isAlias {
+				s.skipped = true
+				s.reason = "completion file already exists (use --force to overwrite)"
+			}
+			continue
+		}
+		if completionUnchanged(target, members[file.Name]) {
+			if !isAlias {
+				s.unchanged = true
+			}
+			continue
+		}
+		if opts.dryRun {
+			s.written = s.written || !isAlias
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			s.addError(cmd.ErrOrStderr(), fmt.Errorf("%s completion: %w", file.Shell, err))
+			continue
+		}
+		backup, err := backupCompletion(target, opts.keepBackups, opts.logger())
+		if err != nil {
+			s.addError(cmd.ErrOrStderr(), fmt.Errorf("%s completion: failed to back up %s: %w", file.Shell, target, err))
+			continue
+		}
+		if backup != "" {
+			s.backups = append(s.backups, backup)
+		}
+		if err := writeCompletionFile(target, members[file.Name], opts.logger()); err != nil {
+			s.addError(cmd.ErrOrStderr(), fmt.Errorf("%s completion: %w", file.Shell, err))
+			continue
+		}
+		if !isAlias {
+			s.written = true
+		}
+	}
+
+	statuses := make([]shellStatus, 0, len(order))
+	for _, sh := range order {
+		statuses = append(statuses, *byShell[sh])
+	}
+	return statuses, nil
+}
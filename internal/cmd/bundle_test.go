@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestInstallBundleRefusesForeignTargets(t *testing.T) {
+	home := t.TempDir()
+	opts := shellOptions{force: true, paths: pathContext{goos: "linux", home: home, configHome: filepath.Join(home, ".config")}}
+	bashPath, err := completionPath("bash", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fishPath, err := completionPath("fish", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := []bundleFile{
+		{Shell: "bash", Name: "completions/bash/arc.bash", Target: opts.paths.homeRelative(bashPath)},
+		{Shell: "fish", Name: "completions/fish/a.fish", Target: opts.paths.homeRelative(filepath.Join(filepath.Dir(fishPath), "a.fish")), Alias: true},
+		{Shell: "bash", Name: "completions/bash/authorized_keys", Target: "$HOME/.ssh/authorized_keys"},
+		{Shell: "bash", Name: "completions/bash/bashrc", Target: "$HOME/.bashrc"},
+		{Shell: "zsh", Name: "completions/zsh/_arc", Target: opts.paths.homeRelative(bashPath)},
+		{Shell: "bash", Name: "completions/bash/other.bash", Target: opts.paths.homeRelative(filepath.Join(filepath.Dir(bashPath), "other.bash")), Alias: true},
+	}
+	contents := make(map[string][]byte)
+	for _, f := range files {
+		contents[f.Name] = []byte("# arc-init " + version + "\n")
+	}
+	manifest := bundleManifest{Version: version, Files: files}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if err := writeBundleArchive(src, manifest, manifestData, nil, contents); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetErr(io.Discard)
+	statuses, err := installBundle(cmd, src, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures := 0
+	for _, s := range statuses {
+		failures += len(s.failures)
+	}
+	if failures != 4 {
+		t.Errorf("got %d refused targets, want 4: %+v", failures, statuses)
+	}
+	for _, path := range []string{bashPath, filepath.Join(filepath.Dir(fishPath), "a.fish")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("legitimate target not installed: %v", err)
+		}
+	}
+	for _, path := range []string{filepath.Join(home, ".ssh", "authorized_keys"), filepath.Join(home, ".bashrc"), filepath.Join(filepath.Dir(bashPath), "other.bash")} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("foreign target %s was written", path)
+		}
+	}
+}
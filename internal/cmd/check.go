@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	driftCurrent = "current"
+	driftStale   = "stale"
+	driftMissing = "missing"
+)
+
+// completionDrift compares the installed completion file for shell with what
+// root generates now, by header version for release builds and byte for byte
+// otherwise. It never writes anything.
+func completionDrift(root *cobra.Command, shell string, opts shellOptions) (string, string, error) {
+	path, err := completionPath(shell, opts)
+	if err != nil {
+		return "", "", err
+	}
+
+	installed, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return path, driftMissing, nil
+	}
+	if err != nil {
+		return path, "", err
+	}
+	state, err := installedDrift(root, shell, path, installed, opts)
+	return path, state, err
+}
+
+// installedDrift is completionDrift for the contents installed at path.
+func installedDrift(root *cobra.Command, shell, path string, installed []byte, opts shellOptions) (string, error) {
+	// A header from another release is stale without regenerating anything.
+	// Development builds all share one version, so they fall back to a diff
+	// of everything below the header.
+	if v := installedVersion(installed); v != version {
+		return driftStale, nil
+	} else if version != "dev" {
+		return driftCurrent, nil
+	}
+
+	want, err := completionBody(root, shell, path, opts)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(stripCompletionHeader(installed), want) {
+		return driftStale, nil
+	}
+	return driftCurrent, nil
+}
+
+// needsRCBlock reports whether shell relies on an RC block to load its
+// completions. Fish auto-loads from its default completions directory, and
+// oh-my-zsh from its completions directory.
+func needsRCBlock(shell string, opts shellOptions) bool {
+	if opts.system || opts.rcPathFor(shell) == "" {
+		return false
+	}
+	if opts.rcFile != "" {
+		return true
+	}
+	if shell == "zsh" && opts.paths.ohMyZsh != "" && opts.outputDir == "" && !opts.system && !opts.usesDataHome("zsh") {
+		return false
+	}
+	if shell == "fish" && opts.usesDataHome("fish") {
+		// vendor_completions.d is on fish's default completion path.
+		return false
+	}
+	if shell == "fish" {
+		return opts.outputDir != "" && filepath.Clean(opts.outputDir) != completionDir("fish", shellOptions{paths: opts.paths})
+	}
+	return true
+}
+
+// rcBlockPresent reports whether path contains an arc-managed RC block.
+func rcBlockPresent(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	content := string(data)
+	return strings.Contains(content, rcStart) && strings.Contains(content, rcEnd)
+}
+
+// checkShells reports whether each shell's installed completion matches the
+// current binary and returns an error if any is stale or missing.
+func checkShells(cmd *cobra.Command, root *cobra.Command, shells []string, opts shellOptions) error {
+	out := cmd.OutOrStdout()
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Shell Completions Check ===")
+	fmt.Fprintln(out)
+
+	var drifted []string
+	for _, sh := range shells {
+		fmt.Fprintf(out, "%s:\n", strings.ToUpper(sh))
+
+		path, state, err := completionDrift(root, sh, opts)
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "  Completions: ERROR (%v)\n", err)
+			drifted = append(drifted, sh)
+		case state == driftMissing:
+			fmt.Fprintf(out, "  Completions: MISSING (%s)\n", path)
+			drifted = append(drifted, sh)
+		case state == driftStale:
+			fmt.Fprintf(out, "  Completions: OUT OF DATE (%s)\n", path)
+			drifted = append(drifted, sh)
+		default:
+			fmt.Fprintf(out, "  Completions: UP TO DATE (%s)\n", path)
+		}
+
+		if needsRCBlock(sh, opts) {
+			if rc := opts.rcPathFor(sh); rcBlockPresent(rc) {
+				fmt.Fprintf(out, "  RC block: PRESENT (%s)\n", rc)
+			} else {
+				fmt.Fprintf(out, "  RC block: MISSING (%s)\n", rc)
+			}
+		}
+		fmt.Fprintln(out)
+	}
+
+	if len(drifted) > 0 {
+		fmt.Fprintf(out, "Run arc-init shell --%s --force to regenerate.\n", strings.Join(drifted, " --"))
+		return fmt.Errorf("completions out of date or missing for: %s", strings.Join(drifted, ", "))
+	}
+
+	fmt.Fprintln(out, "All completions are up to date.")
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+// colorizer wraps status words in ANSI colors when enabled. Disabled
+// colorizers return their input unchanged, so text content never differs.
+type colorizer struct {
+	enabled bool
+}
+
+// newColorizer enables color only when w is a terminal, NO_COLOR is unset,
+// and noColor is false.
+func newColorizer(w io.Writer, noColor bool) colorizer {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return colorizer{}
+	}
+	return colorizer{enabled: isTerminal(w)}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (c colorizer) wrap(code, s string) string {
+	if !c.enabled {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+func (c colorizer) green(s string) string  { return c.wrap(ansiGreen, s) }
+func (c colorizer) yellow(s string) string { return c.wrap(ansiYellow, s) }
+func (c colorizer) red(s string) string    { return c.wrap(ansiRed, s) }
+func (c colorizer) cyan(s string) string   { return c.wrap(ansiCyan, s) }
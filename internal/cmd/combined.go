@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// zshCombinedPrologue makes a sourced zsh completion file work on its own:
+// cobra's script registers itself with compdef, which only exists once
+// compinit has run.
+const zshCombinedPrologue = `# Initialize completion unless the RC file already did.
+(( $+functions[compdef] )) || { autoload -Uz compinit && compinit; }
+
+`
+
+// combinedSourceLine is the command that loads the --combined file at path
+// into shell.
+func combinedSourceLine(shell, path string) string {
+	switch shell {
+	case "bash", "powershell":
+		return `. "` + path + `"`
+	case "elvish":
+		return `eval (slurp < "` + path + `")`
+	case "nushell", "fish", "xonsh", "tcsh", "zsh":
+		return `source "` + path + `"`
+	}
+	return ""
+}
+
+// writeCombined writes the completion for the active shell to the single
+// file path, meant to be sourced directly from a dotfile repo, then prints
+// the line that loads it. No RC file or manifest is touched.
+func writeCombined(cmd *cobra.Command, path string, opts shellOptions) error {
+	shell := opts.currentShell()
+	if shell == "" {
+		return fmt.Errorf("--combined is for the active shell, which could not be detected; pass --shell NAME")
+	}
+	script, err := completionScript(cmd.Root(), shell, path, opts)
+	if err != nil {
+		return fmt.Errorf("%s completion: %w", shell, err)
+	}
+	if shell == "zsh" {
+		script = append([]byte(zshCombinedPrologue), script...)
+	}
+
+	out := cmd.OutOrStdout()
+	if opts.dryRun {
+		fmt.Fprintf(out, "Would write %s completions to %s (dry-run)\n", shell, path)
+	} else {
+		if err := mkdirAll(filepath.Dir(path), opts.logger()); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := writeCompletionFile(path, script, opts.logger()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Fprintf(out, "Wrote %s completions to %s\n", shell, path)
+	}
+	fmt.Fprintln(out, "Load them from your shell's startup file with:")
+	fmt.Fprintf(out, "  %s\n", combinedSourceLine(shell, path))
+	return nil
+}
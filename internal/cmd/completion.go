@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// newCompletionCmd replaces cobra's default completion command with one
+// backed by GenerateCompletion, so piping and installing cannot diverge.
+func newCompletionCmd() *cobra.Command {
+	var noDescriptions bool
+	var commandName string
+
+	cmd := &cobra.Command{
+		Use:   "completion",
+		Short: "Print a shell completion script to stdout",
+		Long: `Print the completion script for a shell to stdout.
+
+Nothing is written to disk and no RC file is touched; use arc-init shell to
+install completions. The script is the same one arc-init shell writes, minus
+the version header: it completes arc, or the name given with --command-name.`,
+		Example: `  eval "$(arc-init completion bash)"
+  source <(arc-init completion zsh)
+  arc-init completion fish | source
+  arc-init completion powershell | Out-String | Invoke-Expression`,
+	}
+
+	for _, sh := range supportedShells {
+		shell := sh
+		cmd.AddCommand(&cobra.Command{
+			Use:   shell,
+			Short: "Print the " + shell + " completion script",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				name := commandName
+				if name == "" {
+					name = completionCommandName
+				} else if !aliasNamePattern.MatchString(name) {
+					return fmt.Errorf("invalid --command-name %q: use letters, digits, '.', '_', '+', or '-'", name)
+				}
+				return generateCompletionScript(cmd.Root(), shell, cmd.OutOrStdout(), genOptions{descriptions: !noDescriptions, name: name})
+			},
+		})
+	}
+
+	cmd.PersistentFlags().BoolVar(&noDescriptions, "no-descriptions", false, "Omit completion descriptions (bash, zsh, fish, PowerShell)")
+	cmd.PersistentFlags().StringVar(&commandName, "command-name", "", "Command the script binds to (default \"arc\")")
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompletionMatchesInstalledScript(t *testing.T) {
+	for _, name := range []string{"", "arc-dev"} {
+		t.Run("command-name="+name, func(t *testing.T) {
+			home := t.TempDir()
+			out := filepath.Join(home, "completions")
+			opts := shellOptions{outputDir: out, commandName: name, paths: pathContext{goos: "linux", home: home, configHome: filepath.Join(home, ".config")}}
+			status := installShell(io.Discard, NewRootCmd(), "bash", opts)
+			if len(status.errs) > 0 {
+				t.Fatalf("install: %v", status.errs)
+			}
+			installed, err := os.ReadFile(status.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			args := []string{"completion", "bash"}
+			if name != "" {
+				args = append(args, "--command-name", name)
+			}
+			var printed bytes.Buffer
+			root := NewRootCmd()
+			root.SetArgs(args)
+			root.SetOut(&printed)
+			if err := root.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := stripCompletionHeader(installed); !bytes.Equal(printed.Bytes(), got) {
+				t.Errorf("arc-init %v differs from %s without its header:\n--- printed\n%s\n--- installed\n%s", args, status.path, printed.Bytes(), got)
+			}
+			if want := "__start_" + opts.command() + " " + opts.command(); !bytes.Contains(printed.Bytes(), []byte(want)) {
+				t.Errorf("printed script does not bind %q", want)
+			}
+		})
+	}
+}
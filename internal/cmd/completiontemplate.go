@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"text/template"
+)
+
+// completionTemplateData is what a --template-file template receives.
+type completionTemplateData struct {
+	// Body is the generated completion script, including any --alias
+	// registrations.
+	Body    string
+	Shell   string
+	Command string
+	Version string
+	// Path is where the file will be installed.
+	Path string
+}
+
+// parseCompletionTemplate compiles the --template-file at path, so a broken
+// template fails the run before anything is written.
+func parseCompletionTemplate(path string) (*template.Template, error) {
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template-file: %w", err)
+	}
+	return tmpl, nil
+}
+
+// applyCompletionTemplate renders body through tmpl for shell. A nil tmpl
+// returns body unchanged.
+func applyCompletionTemplate(tmpl *template.Template, body []byte, shell, path string, opts shellOptions) ([]byte, error) {
+	if tmpl == nil {
+		return body, nil
+	}
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, completionTemplateData{
+		Body:    string(body),
+		Shell:   shell,
+		Command: opts.command(),
+		Version: version,
+		Path:    path,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("--template-file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/yourorg/arc-init/internal/blockedit"
+	"gopkg.in/yaml.v3"
+)
+
+// configSchemaVersion is the layout of config.yaml this build writes. Files
+// without a version key predate versioning and count as schema 1.
+const configSchemaVersion = 2
+
+// configMigration upgrades a config document from schema from to from+1.
+type configMigration struct {
+	from        int
+	description string
+	apply       func(doc *yaml.Node) error
+}
+
+// configMigrations are applied in order by system --migrate. Each one must
+// leave doc valid for the next.
+var configMigrations = []configMigration{
+	{
+		from:        1,
+		description: "record the schema version in config.yaml",
+		apply:       func(*yaml.Node) error { return nil },
+	},
+}
+
+// configVersion returns the schema version recorded in the top-level mapping
+// root of a config document.
+func configVersion(root *yaml.Node) (int, error) {
+	if v := mappingValue(root, "version"); v != nil {
+		n, err := strconv.Atoi(v.Value)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid config version %q", v.Value)
+		}
+		return n, nil
+	}
+	return 1, nil
+}
+
+// setConfigVersion records v in root, adding the key first in the file when
+// it is missing.
+func setConfigVersion(root *yaml.Node, v int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(v)}
+	if mappingValue(root, "version") != nil || len(root.Content) == 0 {
+		setMappingValue(root, "version", value)
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Value: "version"}
+	// Keep the file's leading comment above the new key.
+	key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// migrateSystemConfig upgrades config.yaml at path to configSchemaVersion,
+// backing up the original first. A current config is left untouched.
+func migrateSystemConfig(path string, status *systemStatus, log *slog.Logger) error {
+	status.configPath = path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: expected a YAML mapping at the top level", path)
+	}
+
+	root := doc.Content[0]
+	from, err := configVersion(root)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	status.schemaFrom = from
+	if from > configSchemaVersion {
+		return fmt.Errorf("%s uses config schema %d, but this arc-init only knows up to %d; upgrade arc-init", path, from, configSchemaVersion)
+	}
+	if from == configSchemaVersion {
+		status.configUnchanged = true
+		status.reason = fmt.Sprintf("already at config schema %d", from)
+		return nil
+	}
+
+	for _, m := range configMigrations {
+		if m.from < from {
+			continue
+		}
+		if err := m.apply(&doc); err != nil {
+			return fmt.Errorf("migrate config schema %d to %d: %w", m.from, m.from+1, err)
+		}
+		status.migrations = append(status.migrations, fmt.Sprintf("%d -> %d: %s", m.from, m.from+1, m.description))
+	}
+	setConfigVersion(root, configSchemaVersion)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	backup, err := blockedit.Backup(path, defaultKeepBackups, log)
+	if err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	status.backupPath = backup
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	status.configMigrated = true
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed schema/project-config.schema.json
+var projectConfigSchemaJSON []byte
+
+// jsonSchema is the subset of JSON Schema the project config schema uses:
+// type, properties, required, additionalProperties, items, enum, minimum,
+// maximum, and pattern. Other keywords are ignored.
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *additionalProperties  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []any                  `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	Pattern              string                 `json:"pattern"`
+}
+
+// additionalProperties is either a boolean or a schema for the values of
+// undeclared keys.
+type additionalProperties struct {
+	allowed bool
+	schema  *jsonSchema
+}
+
+func (a *additionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	a.schema = new(jsonSchema)
+	return json.Unmarshal(data, a.schema)
+}
+
+// schemaProblem is one violation found by validateAgainstSchema.
+type schemaProblem struct {
+	line, column int
+	path         string
+	message      string
+}
+
+func (p schemaProblem) String() string {
+	path := p.path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", p.line, p.column, path, p.message)
+}
+
+// loadProjectConfigSchema parses the embedded project config schema.
+func loadProjectConfigSchema() (*jsonSchema, error) {
+	var s jsonSchema
+	if err := json.Unmarshal(projectConfigSchemaJSON, &s); err != nil {
+		return nil, fmt.Errorf("embedded project config schema: %w", err)
+	}
+	return &s, nil
+}
+
+// validateConfigData checks a YAML document against schema. An empty
+// document, such as the all-comments scaffold, is valid.
+func validateConfigData(data []byte, schema *jsonSchema) ([]schemaProblem, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	var problems []schemaProblem
+	validateNode(doc.Content[0], schema, "", &problems)
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].line < problems[j].line })
+	return problems, nil
+}
+
+func validateNode(n *yaml.Node, s *jsonSchema, path string, problems *[]schemaProblem) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	report := func(node *yaml.Node, path, format string, args ...any) {
+		*problems = append(*problems, schemaProblem{line: node.Line, column: node.Column, path: path, message: fmt.Sprintf(format, args...)})
+	}
+
+	if got := yamlType(n); s.Type != "" && !typeMatches(s.Type, got) {
+		report(n, path, "expected %s, got %s", s.Type, got)
+		return
+	}
+
+	if len(s.Enum) > 0 {
+		allowed := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			allowed[i] = fmt.Sprint(v)
+		}
+		if !slices.Contains(allowed, n.Value) {
+			report(n, path, "%q is not one of %s", n.Value, strings.Join(allowed, ", "))
+		}
+	}
+	if s.Minimum != nil || s.Maximum != nil {
+		if v, err := strconv.ParseFloat(n.Value, 64); err == nil {
+			if s.Minimum != nil && v < *s.Minimum {
+				report(n, path, "%s is below the minimum of %g", n.Value, *s.Minimum)
+			}
+			if s.Maximum != nil && v > *s.Maximum {
+				report(n, path, "%s is above the maximum of %g", n.Value, *s.Maximum)
+			}
+		}
+	}
+	if s.Pattern != "" {
+		if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(n.Value) {
+			report(n, path, "%q does not match %s", n.Value, s.Pattern)
+		}
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			seen[key.Value] = true
+			child := joinConfigPath(path, key.Value)
+			if prop, ok := s.Properties[key.Value]; ok {
+				validateNode(value, prop, child, problems)
+				continue
+			}
+			switch ap := s.AdditionalProperties; {
+			case ap == nil || (ap.allowed && ap.schema == nil):
+			case ap.allowed:
+				validateNode(value, ap.schema, child, problems)
+			default:
+				report(key, child, "unknown key")
+			}
+		}
+		for _, req := range s.Required {
+			if !seen[req] {
+				report(n, path, "missing required key %q", req)
+			}
+		}
+	case yaml.SequenceNode:
+		if s.Items != nil {
+			for i, item := range n.Content {
+				validateNode(item, s.Items, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// yamlType names the JSON Schema type of a YAML node.
+func yamlType(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch n.ShortTag() {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+func typeMatches(want, got string) bool {
+	return want == got || (want == "number" && got == "integer")
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// rcDeclinedReason is the RC skip reason when the user answers no.
+const rcDeclinedReason = "declined at the confirmation prompt"
+
+// rcConfirmer asks before each RC file change when running on a terminal.
+// Shells are installed concurrently, so prompts are serialized. A nil
+// rcConfirmer approves everything, which is the --yes and non-TTY behavior.
+type rcConfirmer struct {
+	mu  sync.Mutex
+	in  *bufio.Scanner
+	out io.Writer
+	// all is set once the user answers "a", approving the remaining changes.
+	all bool
+}
+
+func newRCConfirmer(in io.Reader, out io.Writer) *rcConfirmer {
+	return &rcConfirmer{in: bufio.NewScanner(in), out: out}
+}
+
+// setupConfirm makes a run that may change RC files ask first when cmd's
+// output is a terminal. Without one, changes go ahead unless strict, which
+// refuses instead. Dry runs change nothing and never ask.
+func (o *shellOptions) setupConfirm(cmd *cobra.Command, strict bool) error {
+	if o.dryRun {
+		return nil
+	}
+	if isTerminal(cmd.OutOrStdout()) {
+		o.confirm = newRCConfirmer(cmd.InOrStdin(), cmd.OutOrStdout())
+	} else if strict {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("refusing to change RC files without confirmation: no terminal to ask on (pass --yes)")
+	}
+	return nil
+}
+
+// confirm shows the change (a unified diff) that action would make to path
+// and reports whether the user approved it. End of input counts as no.
+func (c *rcConfirmer) confirm(action, path, diff string) bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.all {
+		return true
+	}
+
+	fmt.Fprintln(c.out)
+	fmt.Fprintf(c.out, "arc-init wants to %s %s:\n", action, path)
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		fmt.Fprintf(c.out, "    %s\n", line)
+	}
+	for {
+		fmt.Fprint(c.out, "Apply this change? [y/N/a(ll)] ")
+		if !c.in.Scan() {
+			fmt.Fprintln(c.out)
+			return false
+		}
+		switch strings.ToLower(strings.TrimSpace(c.in.Text())) {
+		case "y", "yes":
+			return true
+		case "", "n", "no":
+			return false
+		case "a", "all":
+			c.all = true
+			return true
+		}
+		fmt.Fprintln(c.out, "Please answer y, n, or a.")
+	}
+}
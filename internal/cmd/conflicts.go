@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// alternativeCompletionPaths lists where package managers typically install
+// arc completions for shell. arc-init never modifies these files.
+func alternativeCompletionPaths(shell string) []string {
+	var prefixes []string
+	if p := os.Getenv("HOMEBREW_PREFIX"); p != "" {
+		prefixes = append(prefixes, p)
+	}
+	prefixes = append(prefixes, "/usr", "/usr/local", "/opt/homebrew")
+
+	var paths []string
+	switch shell {
+	case "bash":
+		for _, p := range prefixes {
+			paths = append(paths,
+				filepath.Join(p, "share", "bash-completion", "completions", "arc"),
+				filepath.Join(p, "etc", "bash_completion.d", "arc"),
+			)
+		}
+		paths = append(paths, "/etc/bash_completion.d/arc")
+	case "zsh":
+		for _, p := range prefixes {
+			paths = append(paths,
+				filepath.Join(p, "share", "zsh", "site-functions", "_arc"),
+				filepath.Join(p, "share", "zsh", "vendor-completions", "_arc"),
+			)
+		}
+	case "fish":
+		for _, p := range prefixes {
+			paths = append(paths, filepath.Join(p, "share", "fish", "vendor_completions.d", "arc.fish"))
+		}
+	}
+	return dedupe(paths)
+}
+
+// findConflictingCompletions returns existing completion files for shell
+// outside the path arc-init manages.
+func findConflictingCompletions(shell, managed string) []string {
+	var found []string
+	for _, p := range alternativeCompletionPaths(shell) {
+		if filepath.Clean(p) == filepath.Clean(managed) {
+			continue
+		}
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+func dedupe(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	out := items[:0]
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	checkPass = "PASS"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+)
+
+type doctorCheck struct {
+	level   string
+	message string
+	fix     string
+}
+
+type doctorReport struct {
+	shell  string
+	active bool
+	checks []doctorCheck
+}
+
+func newDoctorCmd() *cobra.Command {
+	var showEnv bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose shell completion setup",
+		Long: `Diagnose why arc shell completions may not be working.
+
+For each supported shell, doctor checks:
+  - the completion file exists at the expected path
+  - the RC block is present in the shell's RC file (bash, zsh, PowerShell)
+  - the installed completion file matches what this binary generates
+
+The active shell (detected from SHELL) is reported first. Each problem comes
+with the arc-init shell command that fixes it.
+
+--env also prints the environment variables and resolved paths used for
+detection and file placement, ready to paste into a bug report.
+
+Exits non-zero if any check fails so it can gate CI.`,
+		Example: `  arc-init doctor
+  arc-init doctor --env`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := pathsFrom(cmd.Context())
+			if showEnv {
+				reportDoctorEnv(cmd, paths)
+			}
+			reports := runDoctor(cmd.Root(), paths)
+			failed := reportDoctor(cmd, reports)
+			if failed > 0 {
+				return fmt.Errorf("doctor: %d check(s) failed", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&showEnv, "env", false, "Print environment variables and resolved paths used for detection")
+
+	return cmd
+}
+
+// doctorEnvVars are the variables that influence shell detection and where
+// completion and RC files go.
+var doctorEnvVars = []string{"SHELL", "HOME", "XDG_CONFIG_HOME", "ZDOTDIR", "ZSH", "FPATH", "HOMEBREW_PREFIX"}
+
+func reportDoctorEnv(cmd *cobra.Command, paths pathContext) {
+	out := cmd.OutOrStdout()
+	opts := shellOptions{paths: paths}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Environment ===")
+	fmt.Fprintln(out)
+	for _, name := range doctorEnvVars {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			value = "(unset)"
+		}
+		fmt.Fprintf(out, "  %-16s %s\n", name+":", value)
+	}
+	if _, ok := os.LookupEnv("FPATH"); !ok {
+		fmt.Fprintln(out, "  (zsh does not export fpath; run 'print -l $fpath' in zsh to include it)")
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Detection:")
+	fmt.Fprintf(out, "  %-16s %s\n", "Detected shell:", orNone(detectShell(defaultCommandTimeout)))
+	fmt.Fprintf(out, "  %-16s %s\n", "Parent process:", orNone(parentProcessName(defaultCommandTimeout)))
+	fmt.Fprintf(out, "  %-16s %s\n", "zsh framework:", paths.zshFramework())
+	fmt.Fprintf(out, "  %-16s %s\n", "MSYSTEM:", orNone(paths.msystem))
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Resolved paths:")
+	fmt.Fprintf(out, "  %-16s %s\n", "Config home:", paths.configHome)
+	for _, sh := range supportedShells {
+		path, _ := completionPath(sh, opts)
+		fmt.Fprintf(out, "  %-16s %s\n", sh+":", path)
+		if rc := paths.rcPathFor(sh); rc != "" {
+			fmt.Fprintf(out, "  %-16s %s\n", sh+" RC:", rc)
+		}
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func runDoctor(root *cobra.Command, paths pathContext) []doctorReport {
+	active := detectShell(defaultCommandTimeout)
+
+	shells := make([]string, 0, len(supportedShells))
+	if active != "" {
+		shells = append(shells, active)
+	}
+	for _, sh := range supportedShells {
+		if sh != active {
+			shells = append(shells, sh)
+		}
+	}
+
+	reports := make([]doctorReport, 0, len(shells))
+	for _, sh := range shells {
+		reports = append(reports, diagnoseShell(root, sh, sh == active, paths))
+	}
+	return reports
+}
+
+func diagnoseShell(root *cobra.Command, shell string, active bool, paths pathContext) doctorReport {
+	report := doctorReport{shell: shell, active: active}
+	opts := shellOptions{paths: paths}
+	flag := "--" + shell
+
+	path, state, err := completionDrift(root, shell, opts)
+	if err != nil && path == "" {
+		report.checks = append(report.checks, doctorCheck{level: checkFail, message: err.Error()})
+		return report
+	}
+
+	if state == driftMissing {
+		level := checkWarn
+		if active {
+			level = checkFail
+		}
+		report.checks = append(report.checks, doctorCheck{
+			level:   level,
+			message: fmt.Sprintf("completion file missing: %s", path),
+			fix:     "arc-init shell " + flag,
+		})
+		return report
+	}
+	report.checks = append(report.checks, doctorCheck{
+		level:   checkPass,
+		message: fmt.Sprintf("completion file exists: %s", path),
+	})
+
+	switch {
+	case err != nil:
+		report.checks = append(report.checks, doctorCheck{
+			level:   checkWarn,
+			message: fmt.Sprintf("could not compare with current binary output: %v", err),
+		})
+	case state == driftStale:
+		report.checks = append(report.checks, doctorCheck{
+			level:   checkWarn,
+			message: "completion file is stale (differs from current binary output)",
+			fix:     "arc-init shell " + flag + " --force",
+		})
+	default:
+		report.checks = append(report.checks, doctorCheck{
+			level:   checkPass,
+			message: "completion file is up to date",
+		})
+	}
+
+	if !needsRCBlock(shell, opts) {
+		return report
+	}
+
+	rcPath := paths.rcPathFor(shell)
+	if rcBlockPresent(rcPath) {
+		report.checks = append(report.checks, doctorCheck{
+			level:   checkPass,
+			message: fmt.Sprintf("RC block present in %s", rcPath),
+		})
+	} else {
+		report.checks = append(report.checks, doctorCheck{
+			level:   checkFail,
+			message: fmt.Sprintf("RC block missing from %s", rcPath),
+			fix:     "arc-init shell " + flag + " --write-rc",
+		})
+	}
+
+	return report
+}
+
+func reportDoctor(cmd *cobra.Command, reports []doctorReport) int {
+	out := cmd.OutOrStdout()
+	failed := 0
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Arc Completion Doctor ===")
+	fmt.Fprintln(out)
+
+	for _, r := range reports {
+		if r.active {
+			fmt.Fprintf(out, "%s (active shell):\n", strings.ToUpper(r.shell))
+		} else {
+			fmt.Fprintf(out, "%s:\n", strings.ToUpper(r.shell))
+		}
+
+		for _, c := range r.checks {
+			fmt.Fprintf(out, "  [%s] %s\n", c.level, c.message)
+			if c.fix != "" {
+				fmt.Fprintf(out, "         Fix: %s\n", c.fix)
+			}
+			if c.level == checkFail {
+				failed++
+			}
+		}
+		fmt.Fprintln(out)
+	}
+
+	if failed == 0 {
+		fmt.Fprintln(out, "All checks passed.")
+	} else {
+		fmt.Fprintf(out, "%d check(s) failed.\n", failed)
+	}
+
+	return failed
+}
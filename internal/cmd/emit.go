@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// emitIndexName is the index --emit-to writes beside the staged files.
+const emitIndexName = "index.json"
+
+// emitIndex maps each file staged by --emit-to to where it belongs. Targets
+// under the home directory are stored as $HOME/..., as in bundles.
+type emitIndex struct {
+	Version string      `json:"version"`
+	Files   []emitEntry `json:"files"`
+}
+
+type emitEntry struct {
+	Shell string `json:"shell"`
+	Name  string `json:"name"`
+	// Kind is "completion" for a file to deploy at Target, or "rc-block"
+	// for a block to append to the RC file at Target.
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	// Alias marks the fish wrapper files written for --alias.
+	Alias bool `json:"alias,omitempty"`
+}
+
+// emitShells stages the completion files and RC blocks for shells in the flat
+// directory dir for a dotfile manager to deploy, instead of installing them.
+// Completion files are named <shell>-<file name> and blocks
+// <shell>-rc-block, so reruns overwrite the same files.
+func emitShells(cmd *cobra.Command, dir string, shells []string, opts shellOptions) error {
+	root := cmd.Root()
+	index := emitIndex{Version: version}
+	contents := make(map[string][]byte)
+
+	add := func(shell, kind, name, target string, data []byte, alias bool) {
+		index.Files = append(index.Files, emitEntry{Shell: shell, Name: name, Kind: kind, Target: opts.paths.homeRelative(target), Alias: alias})
+		contents[name] = data
+	}
+	for _, sh := range shells {
+		target, err := completionPath(sh, opts)
+		if err != nil {
+			return err
+		}
+		script, err := completionScript(root, sh, target, opts)
+		if err != nil {
+			return fmt.Errorf("%s completion: %w", sh, err)
+		}
+		add(sh, "completion", sh+"-"+filepath.Base(target), target, script, false)
+		if sh == "fish" {
+			aliasFiles := fishAliasFiles(filepath.Dir(target), opts.command(), opts.aliases)
+			paths := make([]string, 0, len(aliasFiles))
+			for p := range aliasFiles {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			for _, p := range paths {
+				add(sh, "completion", sh+"-"+filepath.Base(p), p, aliasFiles[p], true)
+			}
+		}
+
+		if opts.completionsOnly || !needsRCBlock(sh, opts) {
+			continue
+		}
+		rc, block, err := rcBlockFor(sh, opts)
+		if err != nil {
+			return fmt.Errorf("%s RC block: %w", sh, err)
+		}
+		add(sh, "rc-block", sh+"-rc-block", rc, []byte(block), false)
+	}
+
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	contents[emitIndexName] = append(indexData, '\n')
+
+	out := cmd.OutOrStdout()
+	if opts.dryRun {
+		fmt.Fprintf(out, "Would stage in %s (dry-run):\n", dir)
+	} else {
+		if err := mkdirAll(dir, opts.logger()); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		for _, name := range append(entryNames(index.Files), emitIndexName) {
+			if err := writeCompletionFile(filepath.Join(dir, name), contents[name], opts.logger()); err != nil {
+				return fmt.Errorf("failed to stage %s: %w", name, err)
+			}
+		}
+		fmt.Fprintf(out, "Staged in %s:\n", dir)
+	}
+	for _, f := range index.Files {
+		verb := "->"
+		if f.Kind == "rc-block" {
+			verb = "append to"
+		}
+		fmt.Fprintf(out, "  %s %s %s\n", f.Name, verb, f.Target)
+	}
+	fmt.Fprintf(out, "  %s lists these destinations\n", emitIndexName)
+	return nil
+}
+
+func entryNames(files []emitEntry) []string {
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	return names
+}
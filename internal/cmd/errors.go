@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/yourorg/arc-init/internal/blockedit"
+)
+
+// Errors returned (wrapped) by the install helpers so callers can tell
+// failures apart with errors.Is. ErrCompletionExists and ErrRCBlockPresent
+// report skips rather than failures.
+var (
+	ErrUnsupportedShell = errors.New("unknown shell")
+	ErrCompletionExists = errors.New("completion file already exists")
+	ErrRCBlockPresent   = errors.New("RC block already present")
+	ErrSyntaxCheck      = errors.New("generated script failed syntax check")
+	ErrSelfTest         = errors.New("completion self-test failed")
+	ErrShellPanic       = errors.New("internal error")
+	ErrEmptyCompletion  = errors.New("generated completion script is empty")
+	ErrReadOnlyHome     = errors.New("config directory is not writable")
+	ErrSymlinkedRC      = blockedit.ErrSymlink
+)
+
+// Exit statuses of arc-init shell. Any other error exits with ExitFailure.
+const (
+	ExitFailure = 1
+	// ExitShellFailed means at least one selected shell failed; the others
+	// were still installed.
+	ExitShellFailed = 2
+	// ExitNoop means --strict was given and nothing was written or removed.
+	ExitNoop = 3
+	// ExitReadOnlyHome means the config directory, which holds the lock and
+	// install manifest, is not writable, so nothing was attempted.
+	ExitReadOnlyHome = 4
+)
+
+// ExitError is an error that asks main for a specific exit status.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// ReadOnlyHomeError reports that Dir, the directory arc-init keeps its lock
+// and manifest in (or the nearest existing parent it would be created in),
+// rejected a probe write. It matches ErrReadOnlyHome with errors.Is.
+type ReadOnlyHomeError struct {
+	Dir string
+	Err error
+}
+
+func (e *ReadOnlyHomeError) Error() string {
+	return fmt.Sprintf("%s is not writable (%v); keep arc-init's state elsewhere with --config-home DIR, "+
+		"and write completions with --output-dir DIR or --system", e.Dir, e.Err)
+}
+
+func (e *ReadOnlyHomeError) Unwrap() error { return e.Err }
+
+func (e *ReadOnlyHomeError) Is(target error) bool { return target == ErrReadOnlyHome }
+
+func exitErrorf(code int, format string, args ...any) error {
+	return &ExitError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// errorCode classifies err for --json output.
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrUnsupportedShell):
+		return "unsupported_shell"
+	case errors.Is(err, ErrSyntaxCheck):
+		return "syntax_check_failed"
+	case errors.Is(err, ErrSelfTest):
+		return "self_test_failed"
+	case errors.Is(err, ErrEmptyCompletion):
+		return "empty_completion"
+	case errors.Is(err, ErrShellPanic):
+		return "internal_error"
+	case errors.Is(err, ErrReadOnlyHome):
+		return "read_only_home"
+	case errors.Is(err, ErrSymlinkedRC):
+		return "symlinked_rc"
+	case errors.Is(err, fs.ErrPermission):
+		return "permission_denied"
+	case errors.Is(err, fs.ErrNotExist):
+		return "not_found"
+	}
+	return "error"
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fishPathQuery identifies one $fish_complete_path lookup.
+type fishPathQuery struct {
+	bin     string
+	timeout time.Duration
+}
+
+// fishPathCache holds the lookups made so far, since completionDir is called
+// several times per run and each lookup starts a fish process.
+var fishPathCache = struct {
+	sync.Mutex
+	dirs map[fishPathQuery][]string
+}{dirs: make(map[fishPathQuery][]string)}
+
+// fishCompletePath returns the directories in fish's $fish_complete_path,
+// queried from the fish binary bin so that universal and config.fish settings
+// are included. It is nil when bin is "" or the query fails within timeout.
+func fishCompletePath(bin string, timeout time.Duration) []string {
+	if bin == "" {
+		return nil
+	}
+	q := fishPathQuery{bin, timeout}
+	fishPathCache.Lock()
+	defer fishPathCache.Unlock()
+	if dirs, ok := fishPathCache.dirs[q]; ok {
+		return dirs
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var dirs []string
+	if out, err := exec.CommandContext(ctx, bin, "-c", `printf '%s\n' $fish_complete_path`).Output(); err == nil {
+		for _, line := range strings.Split(string(out), "\n") {
+			if line != "" {
+				dirs = append(dirs, filepath.Clean(line))
+			}
+		}
+	}
+	fishPathCache.dirs[q] = dirs
+	return dirs
+}
+
+// fishCompletionDir is the per-user fish completion directory: the first
+// entry of $fish_complete_path that is the default ~/.config/fish/completions
+// or an existing directory under the home directory that arc-init can create
+// files in. fish's generated_completions directory is skipped, since
+// fish_update_completions rewrites it. Without fish, under --profile, or with
+// a config home other than ~/.config (which fish would not search), it is the
+// default under the config home.
+func (o shellOptions) fishCompletionDir() string {
+	p := o.paths
+	def := filepath.Join(p.configHome, "fish", "completions")
+	if p.goos != runtime.GOOS || p.home == "" || p.configHome != filepath.Join(p.home, ".config") {
+		return def
+	}
+	for _, dir := range fishCompletePath(shellBinary("fish", o.shellPaths), o.commandTimeout()) {
+		if dir == def {
+			return def
+		}
+		if !strings.HasPrefix(dir, p.home+string(os.PathSeparator)) || filepath.Base(dir) == "generated_completions" {
+			continue
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() && probeDir(dir) == nil {
+			return dir
+		}
+	}
+	return def
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFishCompletionDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub fish is an sh script")
+	}
+	home := t.TempDir()
+	def := filepath.Join(home, ".config", "fish", "completions")
+	custom := filepath.Join(home, "dotfiles", "fish", "completions")
+	generated := filepath.Join(home, ".local", "share", "fish", "generated_completions")
+	for _, dir := range []string{def, custom, generated} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outside := t.TempDir()
+	missing := filepath.Join(home, "missing")
+
+	tests := []struct {
+		name       string
+		configHome string
+		dirs       []string
+		want       string
+		wantQuery  bool
+	}{
+		{"default first", "", []string{def, custom}, def, true},
+		{"writable dir under home", "", []string{generated, outside, missing, custom, def}, custom, true},
+		{"nothing usable", "", []string{generated, outside, missing}, def, true},
+		{"custom config home", filepath.Join(home, "xdg"), []string{custom}, filepath.Join(home, "xdg", "fish", "completions"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubDir := t.TempDir()
+			marker := filepath.Join(stubDir, "queried")
+			stub := filepath.Join(stubDir, "fish")
+			script := "#!/bin/sh\ntouch '" + marker + "'\nprintf '%s\\n' '" + strings.Join(tt.dirs, "' '") + "'\n"
+			if err := os.WriteFile(stub, []byte(script), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			configHome := tt.configHome
+			if configHome == "" {
+				configHome = filepath.Join(home, ".config")
+			}
+			opts := shellOptions{
+				timeout:    time.Second,
+				shellPaths: map[string]string{"fish": stub},
+				paths:      pathContext{goos: runtime.GOOS, home: home, configHome: configHome},
+			}
+
+			if got := opts.fishCompletionDir(); got != tt.want {
+				t.Errorf("fishCompletionDir() = %q, want %q", got, tt.want)
+			}
+			if _, err := os.Stat(marker); (err == nil) != tt.wantQuery {
+				t.Errorf("fish queried = %v, want %v", err == nil, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestFishCompletePathFollowsOptions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub fish is an sh script")
+	}
+	stubs := t.TempDir()
+	for _, name := range []string{"fish-a", "fish-b"} {
+		script := "#!/bin/sh\necho /" + name + "\n"
+		if err := os.WriteFile(filepath.Join(stubs, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := fishCompletePath(filepath.Join(stubs, "fish-a"), time.Second)
+	b := fishCompletePath(filepath.Join(stubs, "fish-b"), time.Second)
+	if len(a) != 1 || a[0] != "/fish-a" || len(b) != 1 || b[0] != "/fish-b" {
+		t.Errorf("lookups = %v, %v; want each binary's own answer", a, b)
+	}
+}
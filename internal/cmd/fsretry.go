@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"syscall"
+	"time"
+)
+
+// retryPolicy retries filesystem operations that fail with transient errors,
+// as network home directories (NFS, SMB) sometimes report.
+type retryPolicy struct {
+	attempts int
+	delay    time.Duration // before the second attempt; doubles after each
+	sleep    func(time.Duration)
+}
+
+// fsRetry is the policy the write helpers use. It is a variable so retries
+// can be exercised without a flaky filesystem.
+var fsRetry = retryPolicy{attempts: 3, delay: 50 * time.Millisecond, sleep: time.Sleep}
+
+// do runs fn, retrying transient failures with growing delays. Permanent
+// errors such as EACCES or ENOSPC are returned at once.
+func (p retryPolicy) do(log *slog.Logger, op, path string, fn func() error) error {
+	delay := p.delay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.attempts || !isTransientFSError(err) {
+			return err
+		}
+		log.Debug("retry filesystem operation", "op", op, "path", path, "attempt", attempt, "delay", delay, "err", err)
+		p.sleep(delay)
+		delay *= 2
+	}
+}
+
+func isTransientFSError(err error) bool {
+	return errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.EINTR)
+}
+
+// mkdirAll is os.MkdirAll under fsRetry.
+func mkdirAll(dir string, log *slog.Logger) error {
+	return fsRetry.do(log, "mkdir", dir, func() error { return os.MkdirAll(dir, 0o755) })
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeRetry returns a policy that records its sleeps instead of sleeping.
+func fakeRetry(attempts int, delays *[]time.Duration) retryPolicy {
+	return retryPolicy{attempts: attempts, delay: 10 * time.Millisecond, sleep: func(d time.Duration) {
+		*delays = append(*delays, d)
+	}}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	busy := &os.PathError{Op: "rename", Path: "arc.bash", Err: syscall.EBUSY}
+	denied := &os.PathError{Op: "rename", Path: "arc.bash", Err: syscall.EACCES}
+	tests := []struct {
+		name       string
+		failures   []error
+		wantErr    error
+		wantCalls  int
+		wantDelays []time.Duration
+	}{
+		{"success", nil, nil, 1, nil},
+		{"transient then success", []error{busy, busy}, nil, 3, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}},
+		{"transient until exhausted", []error{busy, busy, busy, busy}, syscall.EBUSY, 3, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}},
+		{"permanent", []error{denied}, syscall.EACCES, 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delays []time.Duration
+			calls := 0
+			err := fakeRetry(3, &delays).do(shellOptions{}.logger(), "rename", "arc.bash", func() error {
+				calls++
+				if calls <= len(tt.failures) {
+					return tt.failures[calls-1]
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !slices.Equal(delays, tt.wantDelays) {
+				t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+		})
+	}
+}
+
+func TestWriteCompletionFileRetriesBusyRename(t *testing.T) {
+	var delays []time.Duration
+	saved := fsRetry
+	fsRetry = fakeRetry(3, &delays)
+	failures := 1
+	renameFile = func(from, to string) error {
+		if failures > 0 {
+			failures--
+			return &os.LinkError{Op: "rename", Old: from, New: to, Err: syscall.EBUSY}
+		}
+		return os.Rename(from, to)
+	}
+	t.Cleanup(func() {
+		fsRetry = saved
+		renameFile = os.Rename
+	})
+
+	path := filepath.Join(t.TempDir(), "arc.bash")
+	if err := writeCompletionFile(path, []byte("complete -F _arc arc\n"), shellOptions{}.logger()); err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != 1 {
+		t.Errorf("retries = %d, want 1", len(delays))
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "complete -F _arc arc\n" {
+		t.Errorf("file = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/yourorg/arc-init/internal/blockedit"
+)
+
+const gitignorePath = ".gitignore"
+
+// gitignorePatterns are the entries arc keeps in its .gitignore block.
+var gitignorePatterns = []string{".arc/"}
+
+// gitignoreBlock is the managed .gitignore block, delimited by the same
+// markers as RC blocks.
+func gitignoreBlock() string {
+	return rcStart + "\n" + strings.Join(gitignorePatterns, "\n") + "\n" + rcEnd + "\n"
+}
+
+// addToGitignoreFile adds arc's managed block to .gitignore, creating the
+// file if needed. It reports false when the block is already present or the
+// patterns are already ignored by hand-written lines.
+func addToGitignoreFile(log *slog.Logger) (bool, error) {
+	data, err := os.ReadFile(gitignorePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	content := string(data)
+	if _, _, ok := findRCBlock(content, currentRCMarkers); ok || ignoresAll(content, gitignorePatterns) {
+		return false, nil
+	}
+	return blockedit.Upsert(gitignorePath, rcStart, rcEnd, gitignoreBlock(), blockedit.Options{Log: log})
+}
+
+// removeFromGitignoreFile removes arc's managed block from .gitignore,
+// deleting the file when nothing else is left in it. It reports whether a
+// block was removed.
+func removeFromGitignoreFile(log *slog.Logger) (bool, error) {
+	if _, err := os.Stat(gitignorePath); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	removed, err := blockedit.Remove(gitignorePath, rcStart, rcEnd, blockedit.Options{Log: log})
+	if err != nil || !removed {
+		return removed, err
+	}
+	if rest, err := os.ReadFile(gitignorePath); err == nil && strings.TrimSpace(string(rest)) == "" {
+		return true, os.Remove(gitignorePath)
+	}
+	return true, nil
+}
+
+// ignoresAll reports whether content has a line for every pattern.
+func ignoresAll(content string, patterns []string) bool {
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		lines[strings.TrimSpace(line)] = true
+	}
+	for _, p := range patterns {
+		if !lines[p] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"strings"
+	"time"
+)
+
+// versionMarkerPrefix starts the header line that records which arc-init
+// build generated a completion file.
+const versionMarkerPrefix = "# arc-init version: "
+
+// completionHeaderLines is the number of lines completionHeader emits.
+const completionHeaderLines = 3
+
+// completionHeader returns the comment block written at the top of every
+// completion file. '#' starts a comment in every supported shell.
+func completionHeader(shell string, now time.Time) string {
+	return versionMarkerPrefix + version + "\n" +
+		"# Generated: " + now.UTC().Format(time.RFC3339) + "\n" +
+		"# Do not edit manually; regenerate with: arc-init shell --" + shell + " --force\n"
+}
+
+// withCompletionHeader inserts header into a generated completion script. It
+// goes after a leading #compdef line, which zsh requires to stay first.
+func withCompletionHeader(script []byte, header string) []byte {
+	at := 0
+	if bytes.HasPrefix(script, []byte("#compdef")) {
+		if i := bytes.IndexByte(script, '\n'); i >= 0 {
+			at = i + 1
+		}
+	}
+	out := make([]byte, 0, len(script)+len(header))
+	out = append(out, script[:at]...)
+	out = append(out, header...)
+	return append(out, script[at:]...)
+}
+
+// stripCompletionHeader returns data without the header added by
+// withCompletionHeader, so installed files can be compared with freshly
+// generated scripts regardless of when they were written.
+func stripCompletionHeader(data []byte) []byte {
+	at := 0
+	if bytes.HasPrefix(data, []byte("#compdef")) {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			at = i + 1
+		}
+	}
+	if !bytes.HasPrefix(data[at:], []byte(versionMarkerPrefix)) {
+		return data
+	}
+	end := at
+	for n := 0; n < completionHeaderLines; n++ {
+		i := bytes.IndexByte(data[end:], '\n')
+		if i < 0 {
+			return data
+		}
+		end += i + 1
+	}
+	out := make([]byte, 0, len(data)-(end-at))
+	out = append(out, data[:at]...)
+	return append(out, data[end:]...)
+}
+
+// completionUnchanged reports whether the file at path already holds script.
+// The headers are left out of the checksum, since the generated timestamp
+// differs on every run, but the recorded version must match so upgrades still
+// refresh the header.
+func completionUnchanged(path string, script []byte) bool {
+	data, err := os.ReadFile(path)
+	if err != nil || installedVersion(data) != installedVersion(script) {
+		return false
+	}
+	return sha256.Sum256(stripCompletionHeader(data)) == sha256.Sum256(stripCompletionHeader(script))
+}
+
+// installedVersion returns the version recorded in a completion file's
+// header, or "" when the file predates headers.
+func installedVersion(data []byte) string {
+	for i, line := range strings.SplitN(string(data), "\n", 3) {
+		if i > 1 {
+			break
+		}
+		if v, ok := strings.CutPrefix(line, versionMarkerPrefix); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
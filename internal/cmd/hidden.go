@@ -0,0 +1,27 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// unhiddenTree returns a copy of the command tree under c with every hidden
+// command visible, for --include-hidden-commands. Only the Command structs
+// are copied; flag sets and run functions are shared, which is fine because
+// generators only read them. cobra's own __complete commands stay hidden.
+func unhiddenTree(c *cobra.Command) *cobra.Command {
+	cp := *c
+	subs := c.Commands()
+	cp.ResetCommands()
+	if !strings.HasPrefix(cp.Name(), "__") {
+		cp.Hidden = false
+	}
+	for _, sub := range subs {
+		cp.AddCommand(unhiddenTree(sub))
+	}
+	return &cp
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"path/filepath"
+)
+
+// shellHints returns the manual steps that load s's completion file, for
+// shells whose RC block this run neither wrote nor found in place.
+func shellHints(s shellStatus, opts shellOptions) []string {
+	if s.path == "" {
+		return nil
+	}
+	source := opts.paths.homeRelative(s.path)
+	dir := opts.paths.homeRelative(filepath.Dir(s.path))
+	if s.shell == "bash" && opts.paths.msys() {
+		source = msysPath(source)
+	}
+
+	rc := opts.paths.homeRelative(opts.rcPathFor(s.shell))
+
+	switch s.shell {
+	case "bash":
+		return []string{
+			"add to " + rc + " (or re-run with --write-rc):",
+			`[ -f "` + source + `" ] && . "` + source + `"`,
+		}
+	case "zsh":
+		if !needsRCBlock("zsh", opts) {
+			return []string{"oh-my-zsh loads " + source + " automatically; start a new shell"}
+		}
+		if opts.paths.zshFramework() != zshVanilla {
+			return []string{
+				"add to " + rc + " after " + opts.paths.zshFramework() + " loads (or re-run with --write-rc):",
+				`fpath+=("` + dir + `")`,
+				"autoload -Uz " + completionFileName("zsh", opts) + " && compdef " + completionFileName("zsh", opts) + " " + opts.command(),
+			}
+		}
+		return []string{
+			"add to " + rc + " before compinit runs (or re-run with --write-rc):",
+			`fpath+=("` + dir + `")`,
+			"autoload -Uz compinit && compinit",
+		}
+	case "fish":
+		if needsRCBlock("fish", opts) {
+			return []string{
+				"add to " + rc + " (or re-run with --write-rc):",
+				`set -g fish_complete_path "` + dir + `" $fish_complete_path`,
+			}
+		}
+		return []string{"fish auto-loads " + source + "; start a new fish session"}
+	case "powershell":
+		return []string{
+			"add to $PROFILE (or re-run with --write-rc):",
+			`. "` + source + `"`,
+		}
+	case "nushell":
+		return []string{
+			"add to your config.nu:",
+			"source " + s.path,
+		}
+	case "elvish":
+		return []string{
+			"add to ~/.config/elvish/rc.elv:",
+			"use " + opts.command(),
+		}
+	case "xonsh":
+		return []string{
+			"add to " + rc + " (or re-run with --write-rc):",
+			`source "` + source + `"`,
+		}
+	case "tcsh":
+		return []string{
+			"add to " + rc + " (or re-run with --write-rc):",
+			`if ( -f "` + source + `" ) source "` + source + `"`,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestShellHintsFollowOptions(t *testing.T) {
+	home := t.TempDir()
+	opts := shellOptions{commandName: "arc-dev", paths: pathContext{goos: "linux", home: home, configHome: filepath.Join(home, ".config")}}
+
+	// Without ~/.bashrc the RC file is ~/.bash_profile.
+	bash := shellHints(shellStatus{shell: "bash", path: filepath.Join(home, "arc.bash")}, opts)
+	if len(bash) == 0 || !strings.Contains(bash[0], "$HOME/.bash_profile") {
+		t.Errorf("bash hints = %q, want ~/.bash_profile", bash)
+	}
+	elvish := shellHints(shellStatus{shell: "elvish", path: filepath.Join(home, "arc-dev.elv")}, opts)
+	if len(elvish) < 2 || elvish[1] != "use arc-dev" {
+		t.Errorf("elvish hints = %q, want use arc-dev", elvish)
+	}
+}
+
+func TestNextStepsOmitWiredShells(t *testing.T) {
+	home := t.TempDir()
+	if err := os.WriteFile(filepath.Join(home, ".bashrc"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := shellOptions{paths: pathContext{goos: "linux", home: home, configHome: filepath.Join(home, ".config")}}
+	path := filepath.Join(home, ".config", "bash", "completions", "arc.bash")
+
+	tests := []struct {
+		name   string
+		status shellStatus
+		hint   bool
+	}{
+		{"rc written", shellStatus{shell: "bash", path: path, written: true, rcWritten: true}, false},
+		// The completion file was already there, but the block went in.
+		{"skipped, rc written", shellStatus{shell: "bash", path: path, skipped: true, rcWritten: true}, false},
+		{"rc unchanged", shellStatus{shell: "bash", path: path, written: true, rcSkipped: true, rcUnchanged: true}, false},
+		{"no rc", shellStatus{shell: "bash", path: path, written: true}, true},
+		{"rc declined", shellStatus{shell: "bash", path: path, written: true, rcSkipped: true, rcReason: rcDeclinedReason}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&out)
+			reportShellStatus(cmd, []shellStatus{tt.status}, opts)
+			if got := strings.Contains(out.String(), "add to $HOME/.bashrc"); got != tt.hint {
+				t.Errorf("hint printed = %v, want %v:\n%s", got, tt.hint, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// InstallOptions configures InstallShells. The zero value installs nothing;
+// set Shells to choose what to install.
+type InstallOptions struct {
+	// Root is the command tree to complete; NewRootCmd() when nil.
+	Root   *cobra.Command
+	Shells []string
+
+	// Force overwrites existing completion files, ForceRC rewrites outdated
+	// RC blocks, and WriteRC adds RC blocks where a shell needs one.
+	Force   bool
+	ForceRC bool
+	WriteRC bool
+	DryRun  bool
+
+	// OutputDir replaces the per-shell completion directories. It and
+	// ConfigHome may use ~ and $VAR, as the flags can.
+	OutputDir string
+	// ConfigHome replaces $XDG_CONFIG_HOME, as --config-home does.
+	ConfigHome string
+
+	// Stderr receives each per-shell error as it happens; discarded when nil.
+	Stderr io.Writer
+	Logger *slog.Logger
+
+	// shell carries the complete flag set when the shell command is the
+	// caller; it then owns the state lock and the fields above are unused.
+	shell *shellOptions
+}
+
+// ShellResult is what InstallShells did for one shell.
+type ShellResult struct {
+	Shell string
+	// CompletionPath is the completion file written, or with DryRun the
+	// one that would be.
+	CompletionPath string
+
+	// Written reports a new or rewritten completion file, Unchanged an
+	// identical one already in place, and Skipped an existing file left
+	// alone without Force; Reason says why when set.
+	Written   bool
+	Unchanged bool
+	Skipped   bool
+	Reason    string
+
+	// RCPath is the RC file holding the shell's arc block. RCWritten
+	// reports the block was added or updated; RCSkipped that it was not,
+	// with RCReason saying why.
+	RCPath    string
+	RCWritten bool
+	RCSkipped bool
+	RCReason  string
+
+	// Err joins the shell's failures; nil when it succeeded.
+	Err error
+}
+
+func (s shellStatus) result() ShellResult {
+	return ShellResult{
+		Shell:          s.shell,
+		CompletionPath: s.path,
+		Written:        s.written,
+		Unchanged:      s.unchanged,
+		Skipped:        s.skipped,
+		Reason:         s.reason,
+		RCPath:         s.rcPath,
+		RCWritten:      s.rcWritten,
+		RCSkipped:      s.rcSkipped,
+		RCReason:       s.rcReason,
+		Err:            errors.Join(s.failures...),
+	}
+}
+
+// InstallShells installs completions (and, with WriteRC, RC blocks) for each
+// of opts.Shells, as arc-init shell does, and records them in the install
+// manifest. One shell failing does not stop the others: the returned
+// results cover every shell, and the error joins each failure.
+func InstallShells(opts InstallOptions) ([]ShellResult, error) {
+	statuses, err := installShellStatuses(opts)
+	var results []ShellResult
+	for _, s := range statuses {
+		results = append(results, s.result())
+	}
+	return results, err
+}
+
+// installShellStatuses is InstallShells returning the full statuses the
+// shell command reports from.
+func installShellStatuses(opts InstallOptions) ([]shellStatus, error) {
+	root := opts.Root
+	if root == nil {
+		root = NewRootCmd()
+	}
+	stderr := opts.Stderr
+	if stderr == nil {
+		stderr = io.Discard
+	}
+
+	var so shellOptions
+	if opts.shell != nil {
+		so = *opts.shell
+	} else {
+		outputDir, err := expandPath("OutputDir", opts.OutputDir)
+		if err != nil {
+			return nil, err
+		}
+		configHome, err := expandPath("ConfigHome", opts.ConfigHome)
+		if err != nil {
+			return nil, err
+		}
+		for _, sh := range opts.Shells {
+			if !slices.Contains(supportedShells, sh) {
+				return nil, fmt.Errorf("%w: %s", ErrUnsupportedShell, sh)
+			}
+		}
+		so = shellOptions{
+			force:       opts.Force,
+			forceRC:     opts.ForceRC,
+			writeRC:     opts.WriteRC,
+			dryRun:      opts.DryRun,
+			outputDir:   outputDir,
+			log:         opts.Logger,
+			paths:       newPathContext(configHome),
+			keepBackups: defaultKeepBackups,
+			timeout:     defaultCommandTimeout,
+		}
+		if !so.dryRun {
+			if err := probeConfigHome(so.paths, so.logger()); err != nil {
+				return nil, err
+			}
+			unlock, err := lockShellState(so.paths, so.logger())
+			if err != nil {
+				return nil, err
+			}
+			defer unlock()
+		}
+	}
+
+	statuses := installShells(stderr, root, opts.Shells, so)
+	if !so.dryRun {
+		if err := updateShellManifest(statuses, so.paths); err != nil {
+			fmt.Fprintf(stderr, "warning: failed to update manifest: %v\n", err)
+		}
+	}
+	return statuses, shellFailures(statuses)
+}
+
+// shellFailures joins the errors recorded on statuses, or returns nil when
+// every shell succeeded.
+func shellFailures(statuses []shellStatus) error {
+	var errs []error
+	for _, s := range statuses {
+		errs = append(errs, s.failures...)
+	}
+	return errors.Join(errs...)
+}
+
+// failedShells counts the statuses that recorded an error.
+func failedShells(statuses []shellStatus) int {
+	n := 0
+	for _, s := range statuses {
+		if len(s.failures) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// shellExitStatus maps the outcome of a run to its exit status: an
+// ExitShellFailed error when any shell failed and, under strict, an ExitNoop
+// error when no shell changed anything. Failures were already printed.
+func shellExitStatus(cmd *cobra.Command, statuses []shellStatus, strict bool) error {
+	if n := failedShells(statuses); n > 0 {
+		cmd.SilenceUsage = true
+		return exitErrorf(ExitShellFailed, "%d of %d shells failed", n, len(statuses))
+	}
+	if strict && !anyShellChanged(statuses) {
+		cmd.SilenceUsage = true
+		return exitErrorf(ExitNoop, "nothing to do: every shell was skipped or already up to date (--strict)")
+	}
+	return nil
+}
+
+// anyShellChanged reports whether a status wrote, updated, or removed a file.
+func anyShellChanged(statuses []shellStatus) bool {
+	for _, s := range statuses {
+		if s.written || s.completionRemoved || s.rcWritten || s.rcMigrated || s.rcRemoved || s.profilePath != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallShells(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	out := filepath.Join(home, "completions")
+
+	results, err := InstallShells(InstallOptions{Shells: []string{"bash"}, OutputDir: out, ConfigHome: filepath.Join(home, ".config")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	r := results[0]
+	if r.Shell != "bash" || !r.Written || r.Err != nil {
+		t.Errorf("result = %+v, want bash written without error", r)
+	}
+	if want := filepath.Join(out, "arc.bash"); r.CompletionPath != want {
+		t.Errorf("CompletionPath = %q, want %q", r.CompletionPath, want)
+	}
+
+	if _, err := InstallShells(InstallOptions{Shells: []string{"csh2"}}); !errors.Is(err, ErrUnsupportedShell) {
+		t.Errorf("unknown shell error = %v, want ErrUnsupportedShell", err)
+	}
+}
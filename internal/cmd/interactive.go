@@ -0,0 +1,143 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// shellBinaries lists the executables that indicate a shell is installed.
+var shellBinaries = map[string][]string{
+	"bash":       {"bash"},
+	"zsh":        {"zsh"},
+	"fish":       {"fish"},
+	"powershell": {"pwsh", "powershell"},
+	"nushell":    {"nu"},
+	"elvish":     {"elvish"},
+	"xonsh":      {"xonsh"},
+	"tcsh":       {"tcsh"},
+}
+
+// parseShellPaths parses --shell-path NAME=PATH values into a map from shell
+// to executable.
+func parseShellPaths(values []string) (map[string]string, error) {
+	paths := make(map[string]string, len(values))
+	for _, v := range values {
+		name, path, ok := strings.Cut(v, "=")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("invalid --shell-path %q: use NAME=PATH, e.g. bash=/opt/bash5/bin/bash", v)
+		}
+		shell := name
+		if !slices.Contains(supportedShells, shell) {
+			shell = shellFromName(name)
+		}
+		if shell == "" {
+			return nil, fmt.Errorf("invalid --shell-path %q: unknown shell %q (valid: %s)", v, name, strings.Join(supportedShells, ", "))
+		}
+		path, err := expandPath("--shell-path", path)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("invalid --shell-path %q: %w", v, err)
+		} else if info.IsDir() {
+			return nil, fmt.Errorf("invalid --shell-path %q: %s is a directory", v, path)
+		}
+		paths[shell] = path
+	}
+	return paths, nil
+}
+
+// shellBinary returns the executable for shell: its entry in overrides (from
+// --shell-path) when there is one, else the first of its executables found on
+// PATH, or "" when the shell is not installed.
+func shellBinary(shell string, overrides map[string]string) string {
+	if path, ok := overrides[shell]; ok {
+		return path
+	}
+	for _, bin := range shellBinaries[shell] {
+		if path, err := exec.LookPath(bin); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// installedShells returns the supported shells found on PATH or in
+// overrides, always including current so the active shell can be selected.
+func installedShells(current string, overrides map[string]string) []string {
+	var found []string
+	for _, sh := range supportedShells {
+		if sh == current {
+			found = append(found, sh)
+			continue
+		}
+		if shellBinary(sh, overrides) != "" {
+			found = append(found, sh)
+		}
+	}
+	if len(found) == 0 {
+		return supportedShells
+	}
+	return found
+}
+
+// promptShellSelection shows a numbered checkbox list of candidates and
+// returns the checked ones. Entering numbers toggles them; an empty line or
+// end of input accepts the current selection.
+func promptShellSelection(in io.Reader, out io.Writer, candidates []string, preselected map[string]bool) []string {
+	checked := make(map[string]bool, len(candidates))
+	for _, sh := range candidates {
+		checked[sh] = preselected[sh]
+	}
+
+	scanner := bufio.NewScanner(in)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Select Shells ===")
+	for {
+		fmt.Fprintln(out)
+		for i, sh := range candidates {
+			box := "[ ]"
+			if checked[sh] {
+				box = "[x]"
+			}
+			fmt.Fprintf(out, "  %d) %s %s\n", i+1, box, sh)
+		}
+		fmt.Fprintln(out)
+		fmt.Fprint(out, "Toggle numbers (e.g. 1 3), Enter to confirm: ")
+
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			break
+		}
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			break
+		}
+		for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(candidates) {
+				fmt.Fprintf(out, "Ignoring %q: pick a number from 1 to %d\n", field, len(candidates))
+				continue
+			}
+			sh := candidates[n-1]
+			checked[sh] = !checked[sh]
+		}
+	}
+
+	var chosen []string
+	for _, sh := range candidates {
+		if checked[sh] {
+			chosen = append(chosen, sh)
+		}
+	}
+	return chosen
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// shellListing describes one supported shell for --list-shells.
+type shellListing struct {
+	Shell          string `json:"shell"`
+	Binary         string `json:"binary,omitempty"`
+	Installed      bool   `json:"installed"`
+	Active         bool   `json:"active"`
+	CompletionPath string `json:"completion_path,omitempty"`
+	HasCompletion  bool   `json:"has_completion"`
+}
+
+// listShellsFor reports, for every supported shell, whether its binary is on
+// PATH, whether a completion file exists at the managed path, and whether it
+// is the current shell (see shellOptions.currentShell).
+func listShellsFor(opts shellOptions) []shellListing {
+	active := opts.currentShell()
+	listings := make([]shellListing, 0, len(supportedShells))
+	for _, sh := range supportedShells {
+		l := shellListing{Shell: sh, Active: sh == active}
+		if path := shellBinary(sh, opts.shellPaths); path != "" {
+			l.Binary = path
+			l.Installed = true
+		}
+		if path, err := completionPath(sh, opts); err == nil {
+			l.CompletionPath = path
+			if _, err := os.Stat(path); err == nil {
+				l.HasCompletion = true
+			}
+		}
+		listings = append(listings, l)
+	}
+	return listings
+}
+
+func listShells(cmd *cobra.Command, opts shellOptions) error {
+	listings := listShellsFor(opts)
+	if opts.jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(listings)
+	}
+
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SHELL\tINSTALLED\tCOMPLETION\tACTIVE\tPATH")
+	for _, l := range listings {
+		active := ""
+		if l.Active {
+			active = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", l.Shell, yesNo(l.Installed), yesNo(l.HasCompletion), active, orNone(l.CompletionPath))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// errLockHeld is returned by tryLockFile when another process holds the lock.
+var errLockHeld = errors.New("lock held by another process")
+
+// shellLockPath returns the lock file that serializes arc-init runs which
+// edit RC files and the install manifest. It is a variable so tests can point
+// concurrent runs at a private lock.
+var shellLockPath = func(p pathContext) string {
+	return filepath.Join(filepath.Dir(shellManifestPath(p)), "shell.lock")
+}
+
+// shellLockTimeout bounds how long a run waits for another to finish.
+var shellLockTimeout = 30 * time.Second
+
+const shellLockPoll = 100 * time.Millisecond
+
+// holdShellState checks that the config home is writable, failing with
+// ExitReadOnlyHome when it is not, and then takes the shell lock for a run
+// that may write RC files or the manifest. The returned func releases it.
+func holdShellState(cmd *cobra.Command, opts shellOptions) (func(), error) {
+	if err := probeConfigHome(opts.paths, opts.logger()); err != nil {
+		cmd.SilenceUsage = true
+		return nil, &ExitError{Code: ExitReadOnlyHome, Err: err}
+	}
+	return lockShellState(opts.paths, opts.logger())
+}
+
+// lockShellState takes the shell lock, waiting up to shellLockTimeout for a
+// concurrent run to release it. The returned func releases the lock.
+func lockShellState(p pathContext, log *slog.Logger) (func(), error) {
+	path := shellLockPath(p)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	deadline := time.Now().Add(shellLockTimeout)
+	for {
+		unlock, err := tryLockFile(path)
+		log.Debug("try lock", "path", path, "err", err)
+		if err == nil {
+			return unlock, nil
+		}
+		if !errors.Is(err, errLockHeld) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("another arc-init run holds %s; gave up after %s", path, shellLockTimeout)
+		}
+		time.Sleep(shellLockPoll)
+	}
+}
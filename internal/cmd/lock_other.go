@@ -0,0 +1,26 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+// tryLockFile creates path exclusively and removes it on release. Without
+// flock a crashed run leaves the file behind, and it has to be deleted by
+// hand before the next run can proceed.
+func tryLockFile(path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return nil, errLockHeld
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return func() { os.Remove(path) }, nil
+}
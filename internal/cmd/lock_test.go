@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTryLockFileHeld(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shell.lock")
+	unlock, err := tryLockFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tryLockFile(path); !errors.Is(err, errLockHeld) {
+		t.Fatalf("second tryLockFile error = %v, want errLockHeld", err)
+	}
+
+	unlock()
+	unlock, err = tryLockFile(path)
+	if err != nil {
+		t.Fatalf("tryLockFile after release: %v", err)
+	}
+	unlock()
+}
+
+func TestLockShellStateTimesOut(t *testing.T) {
+	home := t.TempDir()
+	p := pathContext{home: home, configHome: filepath.Join(home, ".config")}
+	saved := shellLockTimeout
+	shellLockTimeout = 150 * time.Millisecond
+	t.Cleanup(func() { shellLockTimeout = saved })
+
+	unlock, err := lockShellState(p, shellOptions{}.logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	_, err = lockShellState(p, shellOptions{}.logger())
+	if err == nil || !strings.Contains(err.Error(), "another arc-init run holds") {
+		t.Fatalf("second lockShellState error = %v, want the lock-held error", err)
+	}
+}
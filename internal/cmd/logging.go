@@ -0,0 +1,38 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+)
+
+type loggerKey struct{}
+
+// newLogger returns a text logger on w at debug level when verbose is set and
+// at warn level otherwise, so normal runs stay quiet.
+func newLogger(w io.Writer, verbose bool) *slog.Logger {
+	level := slog.LevelWarn
+	if verbose {
+		level = slog.LevelDebug
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+func withLogger(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// loggerFrom returns the logger stored in ctx by the root command, or a
+// warn-level stderr logger when none is set.
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if log, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return log
+		}
+	}
+	return newLogger(os.Stderr, false)
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	manifestKindCompletion = "completion"
+	manifestKindRC         = "rc"
+)
+
+// shellManifest records every file arc-init shell has written so that
+// --uninstall can remove exactly those files instead of guessing paths.
+type shellManifest struct {
+	Entries []manifestEntry `json:"entries"`
+}
+
+type manifestEntry struct {
+	Shell     string    `json:"shell"`
+	Kind      string    `json:"kind"`
+	Path      string    `json:"path"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func shellManifestPath(p pathContext) string {
+	dir, err := p.systemConfigDir()
+	if err != nil {
+		dir = filepath.Join(p.configHome, "arc")
+	}
+	return filepath.Join(dir, "shell-manifest.json")
+}
+
+// loadShellManifest reads the manifest at path. A missing manifest is
+// reported as os.ErrNotExist.
+func loadShellManifest(path string) (*shellManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m shellManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+func (m *shellManifest) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// record adds or refreshes the entry for kind/path.
+func (m *shellManifest) record(shell, kind, path string, now time.Time) {
+	for i, e := range m.Entries {
+		if e.Kind == kind && e.Path == path {
+			m.Entries[i].Shell = shell
+			m.Entries[i].UpdatedAt = now
+			return
+		}
+	}
+	m.Entries = append(m.Entries, manifestEntry{Shell: shell, Kind: kind, Path: path, UpdatedAt: now})
+}
+
+// has reports whether the manifest records path under kind.
+func (m *shellManifest) has(kind, path string) bool {
+	for _, e := range m.Entries {
+		if e.Kind == kind && filepath.Clean(e.Path) == filepath.Clean(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *shellManifest) forget(kind, path string) {
+	kept := m.Entries[:0]
+	for _, e := range m.Entries {
+		if e.Kind != kind || e.Path != path {
+			kept = append(kept, e)
+		}
+	}
+	m.Entries = kept
+}
+
+// updateShellManifest folds the outcome of an install run into the manifest.
+func updateShellManifest(statuses []shellStatus, p pathContext) error {
+	path := shellManifestPath(p)
+	m, err := loadShellManifest(path)
+	if errors.Is(err, os.ErrNotExist) {
+		m = &shellManifest{}
+	} else if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, s := range statuses {
+		if s.written || s.unchanged {
+			m.record(s.shell, manifestKindCompletion, s.path, now)
+			for _, p := range s.aliasPaths {
+				m.record(s.shell, manifestKindCompletion, p, now)
+			}
+		}
+		if s.rcWritten {
+			m.record(s.shell, manifestKindRC, s.rcPath, now)
+		}
+		if s.completionRemoved {
+			m.forget(manifestKindCompletion, s.path)
+		}
+		if s.rcRemoved {
+			m.forget(manifestKindRC, s.rcPath)
+		}
+		if s.profileRemoved {
+			m.forget(manifestKindRC, s.profilePath)
+		} else if s.profilePath != "" {
+			m.record(s.shell, manifestKindRC, s.profilePath, now)
+		}
+	}
+
+	return m.save(path)
+}
+
+// uninstallShells removes the completion files and RC blocks listed in the
+// manifest: those of the selected shells when shells were picked, else all
+// of them. Without a manifest it falls back to the default paths of the
+// selected shells. The manifest is deleted once no entries are left.
+func uninstallShells(cmd *cobra.Command, shells []string, opts shellOptions) []shellStatus {
+	path := shellManifestPath(opts.paths)
+	m, err := loadShellManifest(path)
+	loaded := err == nil
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: no manifest at %s, removing default paths\n", path)
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v, removing default paths\n", err)
+		}
+		m = &shellManifest{}
+		for _, sh := range shells {
+			if p, err := completionPath(sh, opts); err == nil {
+				m.Entries = append(m.Entries, manifestEntry{Shell: sh, Kind: manifestKindCompletion, Path: p})
+				if sh == "fish" {
+					for ap := range fishAliasFiles(filepath.Dir(p), opts.command(), opts.aliases) {
+						m.Entries = append(m.Entries, manifestEntry{Shell: sh, Kind: manifestKindCompletion, Path: ap})
+					}
+				}
+			}
+			if p := opts.rcPathFor(sh); p != "" {
+				m.Entries = append(m.Entries, manifestEntry{Shell: sh, Kind: manifestKindRC, Path: p})
+			}
+			if p := opts.paths.loginRCPathFor(sh); sh == "bash" && isBashProfile(p, opts) {
+				m.Entries = append(m.Entries, manifestEntry{Shell: sh, Kind: manifestKindRC, Path: p})
+			}
+		}
+	}
+
+	byShell := make(map[string]*shellStatus)
+	var order []string
+	// done lists the entries whose file or block is gone, or was never
+	// there, so the manifest can drop them.
+	var done []manifestEntry
+	for _, e := range m.Entries {
+		if opts.scopedUninstall && !slices.Contains(shells, e.Shell) {
+			continue
+		}
+		s, ok := byShell[e.Shell]
+		if !ok {
+			s = &shellStatus{shell: e.Shell, dryRun: opts.dryRun}
+			byShell[e.Shell] = s
+			order = append(order, e.Shell)
+		}
+
+		if (e.Kind == manifestKindRC && opts.completionsOnly) || (e.Kind == manifestKindCompletion && opts.rcOnly) {
+			continue
+		}
+
+		switch e.Kind {
+		case manifestKindCompletion:
+			removed, err := removeCompletionFile(e.Path, opts.dryRun)
+			if err != nil {
+				s.addError(cmd.ErrOrStderr(), fmt.Errorf("remove %s completion: %w", e.Shell, err))
+			} else {
+				done = append(done, e)
+			}
+			// Fish records --alias wrappers beside the main file.
+			if filepath.Base(e.Path) != completionFileName(e.Shell, opts) {
+				if removed {
+					s.aliasPaths = append(s.aliasPaths, e.Path)
+				}
+				continue
+			}
+			s.path = e.Path
+			s.completionRemoved = removed
+		case manifestKindRC:
+			if e.Shell == "bash" && isBashProfile(e.Path, opts) {
+				if err := removeBashProfileBlock(s, e.Path, opts); err != nil {
+					s.addError(cmd.ErrOrStderr(), fmt.Errorf("bash login profile: %w", err))
+				} else {
+					done = append(done, e)
+				}
+				continue
+			}
+			s.rcPath = e.Path
+			var removed bool
+			rc, err := opts.rcTarget(e.Path)
+			if err == nil {
+				s.rcPath = rc
+				if diff := rcRemovalDiff(rc); diff != "" && !opts.dryRun && !opts.confirm.confirm("remove the arc block from", rc, diff) {
+					s.rcSkipped = true
+					s.rcReason = rcDeclinedReason
+					continue
+				}
+				removed, err = removeRCBlock(rc, opts.dryRun, opts.keepBackups, opts.logger())
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				s.addError(cmd.ErrOrStderr(), fmt.Errorf("remove %s RC: %w", e.Shell, err))
+				continue
+			}
+			done = append(done, e)
+			if removed {
+				s.rcRemoved = true
+				if opts.dryRun {
+					s.rcDiff = rcRemovalDiff(s.rcPath)
+				}
+				if opts.cleanBackups {
+					s.cleanRCBackups(cmd.ErrOrStderr(), opts)
+				}
+			}
+		}
+	}
+
+	if !opts.dryRun {
+		for _, e := range done {
+			m.forget(e.Kind, e.Path)
+		}
+		if len(m.Entries) == 0 {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to remove manifest: %v\n", err)
+			}
+		} else if loaded {
+			if err := m.save(path); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to update manifest: %v\n", err)
+			}
+		}
+	}
+
+	statuses := make([]shellStatus, 0, len(order))
+	for _, sh := range supportedShells {
+		if s, ok := byShell[sh]; ok {
+			statuses = append(statuses, *s)
+		}
+	}
+	return statuses
+}
+
+// uninstallCompletions deletes the selected shells' completion files. A file
+// is only removed from the path arc itself writes to, and when a manifest
+// exists it must also record the file there; anything else is skipped with a
+// warning. RC blocks are removed too when --uninstall-rc is set.
+func uninstallCompletions(cmd *cobra.Command, shells []string, opts shellOptions) []shellStatus {
+	m, err := loadShellManifest(shellManifestPath(opts.paths))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v, checking managed paths only\n", err)
+		}
+		m = nil
+	}
+
+	statuses := make([]shellStatus, 0, len(shells))
+	for _, sh := range shells {
+		status := shellStatus{shell: sh, dryRun: opts.dryRun, rcPath: opts.rcPathFor(sh)}
+
+		path, err := completionPath(sh, opts)
+		if err != nil {
+			status.addError(cmd.ErrOrStderr(), fmt.Errorf("remove %s completion: %w", sh, err))
+			statuses = append(statuses, status)
+			continue
+		}
+		status.path = path
+
+		if m != nil && !m.has(manifestKindCompletion, path) {
+			status.skipped = true
+			status.reason = "not recorded in the install manifest"
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: skipping %s: %s\n", path, status.reason)
+		} else {
+			removed, err := removeCompletionFile(path, opts.dryRun)
+			if err != nil {
+				status.addError(cmd.ErrOrStderr(), fmt.Errorf("remove %s completion: %w", sh, err))
+			}
+			status.completionRemoved = removed
+			if !removed && err == nil {
+				status.skipped = true
+				status.reason = "no completion file at " + path
+			}
+		}
+
+		if opts.uninstallRC && status.rcPath != "" {
+			var removed bool
+			rc, err := opts.rcTarget(status.rcPath)
+			if err == nil {
+				status.rcPath = rc
+				if diff := rcRemovalDiff(rc); diff != "" && !opts.dryRun && !opts.confirm.confirm("remove the arc block from", rc, diff) {
+					status.rcSkipped = true
+					status.rcReason = rcDeclinedReason
+					statuses = append(statuses, status)
+					continue
+				}
+				removed, err = removeRCBlock(rc, opts.dryRun, opts.keepBackups, opts.logger())
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				status.addError(cmd.ErrOrStderr(), fmt.Errorf("remove %s RC: %w", sh, err))
+			} else if removed {
+				status.rcRemoved = true
+				if opts.dryRun {
+					status.rcDiff = rcRemovalDiff(status.rcPath)
+				}
+				if opts.cleanBackups {
+					status.cleanRCBackups(cmd.ErrOrStderr(), opts)
+				}
+			}
+			if sh == "bash" && isBashProfile(opts.paths.loginRCPathFor("bash"), opts) {
+				if err := removeBashProfileBlock(&status, opts.paths.loginRCPathFor("bash"), opts); err != nil {
+					status.addError(cmd.ErrOrStderr(), fmt.Errorf("bash login profile: %w", err))
+				}
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// removeCompletionFile deletes path, reporting whether a file was present.
+func removeCompletionFile(path string, dryRun bool) (bool, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if dryRun {
+		return true, nil
+	}
+	if err := os.Remove(path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
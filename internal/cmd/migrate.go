@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/yourorg/arc-init/internal/blockedit"
+)
+
+// rcMarkers is a start/end pair delimiting an arc-managed RC block.
+type rcMarkers struct {
+	start string
+	end   string
+}
+
+// legacyRCMarkers lists marker pairs that older or hand-edited blocks may
+// use. Whenever rcStart/rcEnd change, append the previous pair here so
+// existing blocks are replaced in place instead of duplicated.
+var legacyRCMarkers = []rcMarkers{
+	{start: "# >>> arc-init >>>", end: "# <<< arc-init <<<"},
+}
+
+// findRCBlock returns the byte offsets of the block delimited by m in
+// content, including both markers and the end marker's trailing newline.
+func findRCBlock(content string, m rcMarkers) (int, int, bool) {
+	return blockedit.Find(content, m.start, m.end)
+}
+
+// findLegacyRCBlock locates the first block using any legacy marker pair.
+func findLegacyRCBlock(content string) (int, int, bool) {
+	for _, m := range legacyRCMarkers {
+		if start, end, ok := findRCBlock(content, m); ok {
+			return start, end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// migrateShellRC replaces a legacy-marked block in the shell's RC file with
+// the current block, in place. When a current block already exists the
+// legacy copy is simply dropped.
+func migrateShellRC(status *shellStatus, shell string, opts shellOptions) error {
+	if !needsRCBlock(shell, opts) {
+		status.rcSkipped = true
+		status.rcReason = "no RC block needed for " + shell
+		return nil
+	}
+
+	path, block, err := rcBlockFor(shell, opts)
+	if err != nil {
+		return err
+	}
+	if path, err = opts.rcTarget(path); err != nil {
+		return err
+	}
+	status.rcPath = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		status.rcSkipped = true
+		status.rcReason = "RC file does not exist"
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	content := string(data)
+	start, end, ok := findLegacyRCBlock(content)
+	if !ok {
+		status.rcSkipped = true
+		status.rcReason = "no legacy RC block found"
+		return nil
+	}
+
+	replacement := block
+	if _, _, hasCurrent := findRCBlock(content, currentRCMarkers); hasCurrent {
+		replacement = ""
+	}
+	updated := content[:start] + replacement + content[end:]
+
+	status.rcMigrated = true
+	status.rcWritten = true
+	status.rcBlock = replacement
+	if opts.dryRun {
+		status.rcDiff = blockedit.Diff(path, content, updated)
+		return nil
+	}
+
+	if !opts.confirm.confirm("replace the legacy arc block in", path, blockedit.Diff(path, content, updated)) {
+		status.rcMigrated, status.rcWritten, status.rcBlock = false, false, ""
+		status.rcSkipped = true
+		status.rcReason = rcDeclinedReason
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := opts.rcTxn.snapshot(path); err != nil {
+		return err
+	}
+	if _, err := blockedit.Backup(path, opts.keepBackups, opts.logger()); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updated), 0o644)
+}
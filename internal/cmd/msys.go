@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// detectMSYS returns the MSYS subsystem (MINGW64, UCRT64, MSYS, ...) when
+// arc-init runs under Git Bash or MSYS2, and "" otherwise. Both export
+// MSYSTEM to their shells; a binary started some other way inside the same
+// install can still be spotted by the uname they ship.
+func detectMSYS() string {
+	if msystem := os.Getenv("MSYSTEM"); msystem != "" {
+		return msystem
+	}
+	if runtime.GOOS != "windows" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCommandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "uname", "-s").Output()
+	if err != nil {
+		return ""
+	}
+	// e.g. MINGW64_NT-10.0-19045 or MSYS_NT-10.0-19045
+	kernel := strings.ToUpper(strings.TrimSpace(string(out)))
+	for _, prefix := range []string{"MINGW", "MSYS", "UCRT", "CLANG"} {
+		if strings.HasPrefix(kernel, prefix) {
+			name, _, _ := strings.Cut(kernel, "_NT")
+			return name
+		}
+	}
+	return ""
+}
+
+// msys reports whether bash paths follow Git Bash / MSYS2 conventions: a
+// Windows profile with an MSYS environment detected.
+func (p pathContext) msys() bool {
+	return p.goos == "windows" && p.msystem != ""
+}
+
+// msysPath rewrites a Windows path the way MSYS shells spell it, so
+// C:\Users\me\x becomes /c/Users/me/x. $HOME-relative and already-POSIX
+// paths only have their separators normalized.
+func msysPath(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	if len(path) >= 2 && path[1] == ':' && unicode.IsLetter(rune(path[0])) {
+		return "/" + strings.ToLower(path[:1]) + path[2:]
+	}
+	return path
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// nixShells are the shells --nix supports: those whose completions
+// home-manager's own shell setup picks up from $XDG_DATA_HOME.
+var nixShells = []string{"bash", "zsh", "fish"}
+
+// nixDetected reports whether arc-init appears to run under Nix: inside
+// nix-shell or nix develop, or with a /nix/store directory on PATH.
+func nixDetected() bool {
+	if os.Getenv("IN_NIX_SHELL") != "" {
+		return true
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if strings.HasPrefix(dir, "/nix/store/") {
+			return true
+		}
+	}
+	return false
+}
+
+// nixSnippet returns the home-manager settings that load the completions in
+// statuses, for the user to add to home.nix in place of the RC edits --nix
+// skips.
+func nixSnippet(statuses []shellStatus, opts shellOptions) []string {
+	var lines []string
+	for _, s := range statuses {
+		if s.path == "" || !slices.Contains(nixShells, s.shell) {
+			continue
+		}
+		switch s.shell {
+		case "bash":
+			lines = append(lines,
+				"# bash-completion loads "+opts.paths.homeRelative(s.path)+" on demand",
+				"programs.bash.enableCompletion = true;")
+		case "zsh":
+			lines = append(lines,
+				"# fpath must be extended before home-manager runs compinit",
+				"programs.zsh.initContent = lib.mkOrder 550 ''",
+				`  fpath+=("`+opts.paths.homeRelative(filepath.Dir(s.path))+`")`,
+				"'';")
+		case "fish":
+			lines = append(lines,
+				"# fish loads "+opts.paths.homeRelative(s.path)+" automatically",
+				"programs.fish.enable = true;")
+		}
+	}
+	return lines
+}
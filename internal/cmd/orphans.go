@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// orphanCandidates returns every per-user location a completion for shell
+// has been installed to by some combination of flags and detected
+// environment: the ~/.config layout, the $XDG_DATA_HOME layout (including
+// files named after arc-init by older versions), ~/.zsh and
+// oh-my-zsh for zsh, and Git Bash's ~/.bash_completion.d. System and
+// Homebrew locations belong to a package manager and are never scanned.
+func orphanCandidates(shell string, opts shellOptions) []string {
+	base := shellOptions{paths: opts.paths, commandName: opts.commandName}
+	variants := []shellOptions{base}
+
+	withData := base
+	withData.xdgData = true
+	variants = append(variants, withData)
+	if opts.commandName == "" {
+		// Before completions bound to arc by default, data-home files were
+		// named after arc-init.
+		legacyName := withData
+		legacyName.commandName = defaultCommandName
+		variants = append(variants, legacyName)
+	}
+
+	noOhMyZsh := base
+	noOhMyZsh.paths.ohMyZsh = ""
+	variants = append(variants, noOhMyZsh)
+
+	toggledMSYS := base
+	if toggledMSYS.paths.msystem == "" {
+		toggledMSYS.paths.msystem = "MINGW64"
+	} else {
+		toggledMSYS.paths.msystem = ""
+	}
+	variants = append(variants, toggledMSYS)
+
+	var paths []string
+	for _, o := range variants {
+		if p, err := completionPath(shell, o); err == nil && !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// writtenByArcInit reports whether the file at path carries the version
+// header arc-init puts on every completion file, returning that version.
+func writtenByArcInit(path string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	v := installedVersion(head[:n])
+	return v, v != ""
+}
+
+// pruneOrphanCompletions removes arc-init completion files left in a managed
+// location other than the one the current flags install to, typically by an
+// older version with different path conventions. Files without the arc-init
+// header are never touched. Pruned files are dropped from the manifest.
+func pruneOrphanCompletions(cmd *cobra.Command, opts shellOptions) error {
+	out := cmd.OutOrStdout()
+	var statuses []shellStatus
+	failed := 0
+	for _, sh := range supportedShells {
+		current, err := completionPath(sh, opts)
+		if err != nil {
+			continue
+		}
+		for _, p := range orphanCandidates(sh, opts) {
+			if p == current {
+				continue
+			}
+			v, ok := writtenByArcInit(p)
+			opts.logger().Debug("check orphan candidate", "shell", sh, "path", p, "arc_init", ok)
+			if !ok {
+				continue
+			}
+			removed, err := removeCompletionFile(p, opts.dryRun)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "failed to prune %s: %v\n", p, err)
+				failed++
+				continue
+			}
+			if !removed {
+				continue
+			}
+			statuses = append(statuses, shellStatus{shell: sh, path: p, completionRemoved: true})
+			verb := "Pruned"
+			if opts.dryRun {
+				verb = "Would prune"
+			}
+			fmt.Fprintf(out, "%s %s orphan: %s (arc-init %s; current location is %s)\n", verb, sh, p, v, current)
+		}
+	}
+
+	if len(statuses) == 0 && failed == 0 {
+		fmt.Fprintln(out, "No orphaned completion files found.")
+	}
+	if !opts.dryRun && len(statuses) > 0 {
+		if err := updateShellManifest(statuses, opts.paths); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to update manifest: %v\n", err)
+		}
+	}
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return exitErrorf(ExitShellFailed, "failed to prune %d orphaned completion files", failed)
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+type pathsKey struct{}
+
+// pathContext holds the base directories every path helper resolves against.
+// It is built once per invocation so --config-home applies everywhere and no
+// helper reads HOME or XDG_CONFIG_HOME on its own.
+type pathContext struct {
+	// goos selects OS-specific conventions; the host OS unless --profile
+	// overrides it.
+	goos       string
+	home       string
+	configHome string
+	// dataHome is $XDG_DATA_HOME, or ~/.local/share when unset.
+	dataHome string
+	// zdotdir is $ZDOTDIR, or home when unset.
+	zdotdir string
+
+	// ohMyZsh and prezto are the zsh framework directories, when installed.
+	ohMyZsh string
+	prezto  string
+
+	// homebrewPrefix is $HOMEBREW_PREFIX, set by `brew shellenv`.
+	homebrewPrefix string
+	// msystem is the Git Bash / MSYS2 subsystem (e.g. MINGW64), when
+	// detected.
+	msystem string
+}
+
+// newPathContext resolves the base directories. configHome overrides
+// $XDG_CONFIG_HOME, which in turn overrides ~/.config.
+func newPathContext(configHome string) pathContext {
+	home, _ := os.UserHomeDir()
+	if configHome == "" {
+		configHome = os.Getenv("XDG_CONFIG_HOME")
+	}
+	if configHome == "" && home != "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" && home != "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	zdotdir := os.Getenv("ZDOTDIR")
+	if zdotdir == "" {
+		zdotdir = home
+	}
+	ohMyZsh, prezto := detectZshFrameworks(home, zdotdir)
+	return pathContext{
+		goos:           runtime.GOOS,
+		home:           home,
+		configHome:     configHome,
+		dataHome:       dataHome,
+		zdotdir:        zdotdir,
+		ohMyZsh:        ohMyZsh,
+		prezto:         prezto,
+		homebrewPrefix: os.Getenv("HOMEBREW_PREFIX"),
+		msystem:        detectMSYS(),
+	}
+}
+
+// profileOS maps --profile values to GOOS names.
+var profileOS = map[string]string{
+	"linux":   "linux",
+	"macos":   "darwin",
+	"windows": "windows",
+}
+
+// withProfile returns p using the path conventions of profile.
+func (p pathContext) withProfile(profile string) (pathContext, error) {
+	goos, ok := profileOS[profile]
+	if !ok {
+		return p, fmt.Errorf("unknown profile %q (valid: linux, macos, windows)", profile)
+	}
+	p.goos = goos
+	return p, nil
+}
+
+func withPaths(ctx context.Context, p pathContext) context.Context {
+	return context.WithValue(ctx, pathsKey{}, p)
+}
+
+// pathsFrom returns the path context stored in ctx by the root command, or
+// one resolved from the environment when none is set.
+func pathsFrom(ctx context.Context) pathContext {
+	if ctx != nil {
+		if p, ok := ctx.Value(pathsKey{}).(pathContext); ok {
+			return p
+		}
+	}
+	return newPathContext("")
+}
+
+// systemConfigDir resolves the global arc config directory. Both init and
+// --print use it so they cannot diverge.
+func (p pathContext) systemConfigDir() (string, error) {
+	if p.configHome == "" {
+		return "", fmt.Errorf("failed to get home directory")
+	}
+	return filepath.Join(p.configHome, "arc"), nil
+}
+
+// dataCompletionDir returns the per-user completion directory under
+// $XDG_DATA_HOME that bash-completion, zsh packages, and fish use, and false
+// for shells without one.
+func (p pathContext) dataCompletionDir(shell string) (string, bool) {
+	if p.dataHome == "" {
+		return "", false
+	}
+	switch shell {
+	case "bash":
+		return filepath.Join(p.dataHome, "bash-completion", "completions"), true
+	case "zsh":
+		return filepath.Join(p.dataHome, "zsh", "site-functions"), true
+	case "fish":
+		return filepath.Join(p.dataHome, "fish", "vendor_completions.d"), true
+	}
+	return "", false
+}
+
+// rcPathFor returns the RC file arc manages for shell, or "" when the shell
+// has no RC integration.
+func (p pathContext) rcPathFor(shell string) string {
+	switch shell {
+	case "bash":
+		return p.bashRCPath()
+	case "zsh":
+		return p.zshRCPath()
+	case "fish":
+		return p.fishRCPath()
+	case "powershell":
+		return p.powershellProfilePath()
+	case "xonsh":
+		return p.xonshRCPath()
+	case "tcsh":
+		return p.tcshRCPath()
+	}
+	return ""
+}
+
+func (p pathContext) bashRCPath() string {
+	rc := filepath.Join(p.home, ".bashrc")
+	if _, err := os.Stat(rc); errors.Is(err, os.ErrNotExist) {
+		return filepath.Join(p.home, ".bash_profile")
+	}
+	return rc
+}
+
+func (p pathContext) zshRCPath() string {
+	return filepath.Join(p.zshDir(), ".zshrc")
+}
+
+// loginRCPathFor returns the startup file login shells read for shell, or ""
+// when shell has no login/interactive split. For bash it is the first of
+// ~/.bash_profile, ~/.bash_login, and ~/.profile that exists, since bash
+// reads only that one; a new ~/.bash_profile would shadow ~/.profile.
+func (p pathContext) loginRCPathFor(shell string) string {
+	switch shell {
+	case "bash":
+		for _, name := range []string{".bash_profile", ".bash_login", ".profile"} {
+			path := filepath.Join(p.home, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+		return filepath.Join(p.home, ".bash_profile")
+	case "zsh":
+		return filepath.Join(p.zshDir(), ".zprofile")
+	}
+	return ""
+}
+
+// interactiveRCPathFor returns the startup file interactive non-login shells
+// read for shell, or "" when shell has no login/interactive split.
+func (p pathContext) interactiveRCPathFor(shell string) string {
+	switch shell {
+	case "bash":
+		return filepath.Join(p.home, ".bashrc")
+	case "zsh":
+		return p.zshRCPath()
+	}
+	return ""
+}
+
+// zshDir returns the directory zsh reads its startup files from.
+func (p pathContext) zshDir() string {
+	if p.zdotdir != "" {
+		return p.zdotdir
+	}
+	return p.home
+}
+
+func (p pathContext) fishRCPath() string {
+	return filepath.Join(p.configHome, "fish", "conf.d", "arc.fish")
+}
+
+// xonshRCPath returns arc's own file in xonsh's rc.d directory, which xonsh
+// runs at startup after rc.xsh.
+func (p pathContext) xonshRCPath() string {
+	return filepath.Join(p.configHome, "xonsh", "rc.d", "arc.xsh")
+}
+
+// tcshRCPath returns ~/.tcshrc, or ~/.cshrc when only that exists: tcsh reads
+// .cshrc only in the absence of .tcshrc, so creating one would hide the other.
+func (p pathContext) tcshRCPath() string {
+	rc := filepath.Join(p.home, ".tcshrc")
+	if _, err := os.Stat(rc); errors.Is(err, os.ErrNotExist) {
+		cshrc := filepath.Join(p.home, ".cshrc")
+		if _, err := os.Stat(cshrc); err == nil {
+			return cshrc
+		}
+	}
+	return rc
+}
+
+// powershellProfilePath returns the location of $PROFILE.CurrentUserAllHosts
+// for PowerShell 7+ on the current OS.
+func (p pathContext) powershellProfilePath() string {
+	if p.goos == "windows" {
+		return filepath.Join(p.home, "Documents", "PowerShell", "profile.ps1")
+	}
+	return filepath.Join(p.configHome, "powershell", "profile.ps1")
+}
+
+// homeRelative rewrites paths under the home directory as $HOME/..., which
+// every supported shell expands inside double quotes.
+func (p pathContext) homeRelative(path string) string {
+	if p.home == "" {
+		return path
+	}
+	rel, err := filepath.Rel(p.home, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return "$HOME/" + filepath.ToSlash(rel)
+}
+
+// probeConfigHome checks that arc-init can write its state directory by
+// creating and removing a file in it, or in its nearest existing parent when
+// it does not exist yet. Permission bits alone miss read-only mounts, which
+// are common for $HOME in container images.
+func probeConfigHome(p pathContext, log *slog.Logger) error {
+	dir := filepath.Dir(shellManifestPath(p))
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	err := probeDir(dir)
+	log.Debug("probe config directory", "dir", dir, "err", err)
+	if err != nil {
+		if pe, ok := err.(*fs.PathError); ok {
+			err = pe.Err
+		}
+		return &ReadOnlyHomeError{Dir: dir, Err: err}
+	}
+	return nil
+}
+
+// probeDir creates and removes a file in dir, returning the error when it
+// cannot.
+func probeDir(dir string) error {
+	f, err := os.CreateTemp(dir, ".arc-write-check-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// expandPath expands a leading ~ and $VAR or ${VAR} references in a
+// user-supplied path, which reach arc-init literally when quoted on the
+// command line or set from a script. name is the flag the path came from,
+// for errors. An empty path stays empty (the flag was not given); a path that
+// expands to nothing, or to the filesystem root, is an error rather than a
+// surprise write to the current or root directory.
+func expandPath(name, path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	expanded := path
+	if expanded == "~" || strings.HasPrefix(expanded, "~/") || strings.HasPrefix(expanded, `~\`) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("%s %q: cannot expand ~: %w", name, path, err)
+		}
+		expanded = home + expanded[1:]
+	} else if strings.HasPrefix(expanded, "~") {
+		return "", fmt.Errorf("%s %q: only ~ for the current user is supported", name, path)
+	}
+
+	var unset []string
+	expanded = os.Expand(expanded, func(v string) string {
+		value, ok := os.LookupEnv(v)
+		if !ok {
+			unset = append(unset, "$"+v)
+		}
+		return value
+	})
+	if len(unset) > 0 {
+		return "", fmt.Errorf("%s %q: %s not set", name, path, strings.Join(unset, ", "))
+	}
+
+	if strings.TrimSpace(expanded) == "" {
+		return "", fmt.Errorf("%s %q expands to an empty path", name, path)
+	}
+	if clean := filepath.Clean(expanded); clean == string(filepath.Separator) || clean == filepath.VolumeName(clean)+string(filepath.Separator) {
+		return "", fmt.Errorf("%s %q expands to the filesystem root %s", name, path, clean)
+	}
+	return expanded, nil
+}
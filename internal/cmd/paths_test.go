@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestZshPathsFollowZDOTDIR(t *testing.T) {
+	home, zdotdir := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ZDOTDIR", zdotdir)
+	t.Setenv("ZSH", "")
+	p := newPathContext("")
+
+	if got := p.zshDir(); got != zdotdir {
+		t.Errorf("zshDir() = %q, want %q", got, zdotdir)
+	}
+	if got, want := p.zshRCPath(), filepath.Join(zdotdir, ".zshrc"); got != want {
+		t.Errorf("zshRCPath() = %q, want %q", got, want)
+	}
+	if got, want := p.loginRCPathFor("zsh"), filepath.Join(zdotdir, ".zprofile"); got != want {
+		t.Errorf(`loginRCPathFor("zsh") = %q, want %q`, got, want)
+	}
+
+	opts := shellOptions{paths: p}
+	want := filepath.Join(zdotdir, ".zsh", "completions", "_arc")
+	if got, err := completionPath("zsh", opts); err != nil || got != want {
+		t.Errorf(`completionPath("zsh") = %q, %v; want %q`, got, err, want)
+	}
+	status := installShell(io.Discard, NewRootCmd(), "zsh", opts)
+	if len(status.errs) > 0 {
+		t.Fatalf("install: %v", status.errs)
+	}
+	if status.path != want {
+		t.Errorf("installed to %q, want %q", status.path, want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("completion not written under ZDOTDIR: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".zsh")); err == nil {
+		t.Errorf("%s/.zsh created despite ZDOTDIR", home)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// rcUpToDateReason is the RC skip reason when the block needs no change.
+const rcUpToDateReason = "RC block already up to date"
+
+// installPlan is the --plan output: every operation a run with the same flags
+// would perform, derived from a dry run of the real installer so the two
+// cannot disagree. It carries no timestamps, and paths under the home
+// directory are written as $HOME/..., so plans from different machines can
+// be diffed directly.
+type installPlan struct {
+	Version    string   `json:"version"`
+	OS         string   `json:"os"`
+	Operations []planOp `json:"operations"`
+}
+
+// planOp is one operation on one file. Action is what the run would do
+// (create_file, replace_file, remove_file, append_rc_block, update_rc_block,
+// migrate_rc_block, remove_rc_block, none, skip, or error); Current and
+// Desired describe the file before and after.
+type planOp struct {
+	Shell   string `json:"shell"`
+	Action  string `json:"action"`
+	Path    string `json:"path,omitempty"`
+	Current string `json:"current,omitempty"`
+	Desired string `json:"desired,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// buildInstallPlan turns the statuses of a dry run into plan operations.
+func buildInstallPlan(statuses []shellStatus, p pathContext) installPlan {
+	plan := installPlan{Version: version, OS: p.goos, Operations: []planOp{}}
+	add := func(op planOp) {
+		op.Path = p.homeRelative(op.Path)
+		if op.Diff != "" && p.home != "" {
+			op.Diff = strings.ReplaceAll(op.Diff, p.home+string(os.PathSeparator), "$HOME/")
+		}
+		plan.Operations = append(plan.Operations, op)
+	}
+
+	for _, s := range statuses {
+		if op, ok := completionPlanOp(s); ok {
+			add(op)
+		}
+		if op, ok := rcPlanOp(s); ok {
+			add(op)
+		}
+		for _, b := range s.backupsRemoved {
+			add(planOp{Shell: s.shell, Action: "remove_file", Path: b, Current: "present", Desired: "absent",
+				Reason: "arc-init RC backup (--clean-backups)"})
+		}
+		if s.profileRemoved {
+			add(planOp{Shell: s.shell, Action: "remove_rc_block", Path: s.profilePath,
+				Current: "block", Desired: "no block"})
+		} else if s.profilePath != "" {
+			add(planOp{Shell: s.shell, Action: "append_rc_block", Path: s.profilePath,
+				Current: "does not load .bashrc", Desired: "loads .bashrc",
+				Reason: "login shells must load the bash RC block"})
+		}
+		for _, e := range s.errs {
+			add(planOp{Shell: s.shell, Action: "error", Reason: e})
+		}
+	}
+	return plan
+}
+
+func completionPlanOp(s shellStatus) (planOp, bool) {
+	op := planOp{Shell: s.shell, Path: s.path}
+	switch {
+	case s.completionRemoved:
+		op.Action, op.Current, op.Desired = "remove_file", "present", "absent"
+	case s.written:
+		op.Action, op.Current, op.Desired = "create_file", "missing", "generated"
+		if _, err := os.Stat(s.path); err == nil {
+			op.Action, op.Current = "replace_file", "differs"
+		}
+	case s.unchanged:
+		op.Action, op.Current, op.Desired = "none", "generated", "generated"
+	case s.skipped:
+		op.Action, op.Reason = "skip", s.reason
+		if !s.shellMissing {
+			op.Current = "present"
+		}
+	default:
+		return planOp{}, false
+	}
+	return op, true
+}
+
+func rcPlanOp(s shellStatus) (planOp, bool) {
+	op := planOp{Shell: s.shell, Path: s.rcPath, Diff: s.rcDiff}
+	switch {
+	case s.rcMigrated:
+		op.Action, op.Current, op.Desired = "migrate_rc_block", "legacy block", "current block"
+	case s.rcWritten && s.rcUpdated:
+		op.Action, op.Current, op.Desired = "update_rc_block", "outdated block", "current block"
+	case s.rcWritten:
+		op.Action, op.Current, op.Desired = "append_rc_block", "no block", "current block"
+	case s.rcRemoved && s.rcDiff != "":
+		op.Action, op.Current, op.Desired = "remove_rc_block", "block", "no block"
+	case s.rcRemoved:
+		op.Action, op.Current, op.Desired = "none", "no block", "no block"
+	case s.rcUnchanged:
+		op.Action, op.Current, op.Desired = "none", "current block", "current block"
+	case s.rcSkipped:
+		op.Action, op.Reason = "skip", s.rcReason
+	default:
+		return planOp{}, false
+	}
+	return op, true
+}
+
+// reportShellPlan prints the --plan JSON for statuses.
+func reportShellPlan(cmd *cobra.Command, statuses []shellStatus, opts shellOptions) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(buildInstallPlan(statuses, opts.paths))
+}
@@ -5,23 +5,40 @@ package cmd
 
 import (
 	"bufio"
+	"embed"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+//go:embed templates/project/*.yaml
+var projectTemplates embed.FS
+
 type projectStatus struct {
 	created        bool
 	merged         bool
 	unchanged      bool
 	reason         string
 	addedKeys      []string
+	keptKeys       []string
+	overwritten    bool
 	gitignoreAdded bool
+	gitignoreNoop  bool
 	configPath     string
+	template       string
+	detected       []string
+	detectNote     string
+	// layoutCreated and layoutSkipped are the --scaffold placeholder files
+	// written and the ones left alone because they already existed.
+	layoutCreated []string
+	layoutSkipped []string
 }
 
 func newProjectCmd() *cobra.Command {
@@ -30,6 +47,12 @@ func newProjectCmd() *cobra.Command {
 		force       bool
 		gitignore   bool
 		scaffold    bool
+		detect      bool
+		uninstallGI bool
+		validate    bool
+		merge       bool
+		overwrite   bool
+		template    string
 	)
 
 	cmd := &cobra.Command{
@@ -45,31 +68,104 @@ global settings from ~/.config/arc/config.yaml. Configuration is searched in ord
   3. Environment variables (ARC_*)
   4. Default values
 
-Idempotent: Running multiple times is safe. Existing configs are not overwritten.
-Use --force to replace entirely.
+Idempotent: Running multiple times is safe. Existing configs are not
+overwritten: by default (--merge) the keys a template or the wizard would set
+are added only where .arc/config.yaml lacks them, keeping your values and
+comments, and the report lists which keys were added and which were left
+untouched. --merge=false leaves an existing config alone entirely;
+--overwrite (or --force) replaces it.
+
+--template writes a ready-to-use config from one of the built-in templates
+(minimal, service, library) instead of the commented-out scaffold. It implies
+--scaffold.
+
+--scaffold also lays out the rest of the conventional .arc/ directory:
+.arc/hooks/ and .arc/templates/, each with a placeholder README. Files that
+already exist are skipped, so edits survive reruns; --force (or --overwrite)
+rewrites them. The report lists which paths were created and which skipped.
+
+--detect picks the template from the files in the current directory: a
+Dockerfile selects service, a single go.mod, package.json, or Cargo.toml
+selects library, and anything else falls back to minimal. An explicit
+--template wins over detection.
 
 The .arc/ directory can be committed to git for team collaboration or added
-to .gitignore for project-local settings.`,
+to .gitignore for project-local settings. --gitignore adds it inside a
+marker-delimited block, so reruns never duplicate it and
+--uninstall-gitignore removes exactly that block.
+
+--validate checks the existing .arc/config.yaml against the embedded config
+schema instead of writing anything, and exits non-zero if it is invalid (see
+arc-init validate).`,
 		Example: `  arc-init project --interactive
   arc-init project --scaffold
   arc-init project --scaffold --gitignore
-  arc-init project --force`,
+  arc-init project --uninstall-gitignore
+  arc-init project --template service
+  arc-init project --detect
+  arc-init project --validate
+  arc-init project --template service --merge
+  arc-init project --overwrite`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			log := loggerFrom(cmd.Context())
+			if validate {
+				cmd.SilenceUsage = true
+				return validateProjectConfig(cmd, defaultProjectConfig)
+			}
+			if uninstallGI {
+				removed, err := removeFromGitignoreFile(log)
+				if err != nil {
+					return fmt.Errorf("failed to update .gitignore: %w", err)
+				}
+				if removed {
+					fmt.Fprintln(cmd.OutOrStdout(), ".gitignore - Removed arc block")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), ".gitignore - No arc block found")
+				}
+				return nil
+			}
+
 			if scaffold && interactive {
 				return fmt.Errorf("cannot use both --scaffold and --interactive")
 			}
 
+			var status projectStatus
+			if detect {
+				if interactive {
+					return fmt.Errorf("cannot use both --detect and --interactive")
+				}
+				if template == "" {
+					d := detectProjectTemplate(".")
+					template = d.template
+					status.detected = d.markers
+					status.detectNote = d.note
+				}
+			}
+
+			if template != "" {
+				if interactive {
+					return fmt.Errorf("cannot use both --template and --interactive")
+				}
+				if _, err := loadProjectTemplate(template); err != nil {
+					return err
+				}
+				scaffold = true
+			}
+
 			if !scaffold {
 				interactive = true
 			}
 
-			var status projectStatus
+			mode := projectWriteMode{overwrite: force || overwrite, merge: merge}
 			if interactive {
-				if err := runInteractiveProject(force, &status); err != nil {
+				if err := runInteractiveProject(mode, &status, log); err != nil {
 					return err
 				}
 			} else {
-				if err := runScaffoldProject(gitignore, force, &status); err != nil {
+				if err := runScaffoldProject(template, gitignore, mode, &status, log); err != nil {
+					return err
+				}
+				if err := scaffoldProjectLayout(mode.overwrite, &status, log); err != nil {
 					return err
 				}
 			}
@@ -80,24 +176,43 @@ to .gitignore for project-local settings.`,
 	}
 
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Interactive setup wizard (default)")
-	cmd.Flags().BoolVar(&scaffold, "scaffold", false, "Create config scaffold only (user edits manually)")
+	cmd.Flags().BoolVar(&scaffold, "scaffold", false, "Create the .arc/ layout (config scaffold, hooks/, templates/) for manual editing")
 	cmd.Flags().BoolVarP(&gitignore, "gitignore", "g", false, "Add .arc/ to .gitignore")
-	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force overwrite existing config")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force overwrite existing config and scaffold placeholders (same as --overwrite)")
+	cmd.Flags().BoolVar(&merge, "merge", true, "Add missing default keys to an existing config, keeping its values and comments")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace an existing config instead of merging into it")
+	cmd.Flags().BoolVar(&uninstallGI, "uninstall-gitignore", false, "Remove arc's block from .gitignore and exit")
+	cmd.Flags().BoolVar(&validate, "validate", false, "Check .arc/config.yaml against the config schema and exit")
+	cmd.Flags().BoolVar(&detect, "detect", false, "Choose the template from project files (go.mod, package.json, Cargo.toml, Dockerfile)")
+	cmd.Flags().StringVar(&template, "template", "", "Scaffold from a built-in template ("+strings.Join(projectTemplateNames(), ", ")+")")
 
 	return cmd
 }
 
-func runInteractiveProject(force bool, status *projectStatus) error {
+// projectWriteMode says what project init does with an existing config:
+// replace it, merge missing default keys into it, or leave it alone.
+type projectWriteMode struct {
+	overwrite bool
+	merge     bool
+}
+
+func runInteractiveProject(mode projectWriteMode, status *projectStatus, log *slog.Logger) error {
 	arcDir := ".arc"
 	configFile := filepath.Join(arcDir, "config.yaml")
 	status.configPath = configFile
 
 	var existingConfig map[string]interface{}
+	var existingData []byte
 	fileExists := false
 
 	if _, err := os.Stat(configFile); err == nil {
 		fileExists = true
-		if !force {
+		if !mode.overwrite {
+			if !mode.merge {
+				status.unchanged = true
+				status.reason = "config already exists (use --merge or --overwrite to update)"
+				return nil
+			}
 			data, err := os.ReadFile(configFile)
 			if err != nil {
 				return err
@@ -105,6 +220,7 @@ func runInteractiveProject(force bool, status *projectStatus) error {
 			if err := yaml.Unmarshal(data, &existingConfig); err != nil {
 				return err
 			}
+			existingData = data
 			if hasAllProjectKeys(existingConfig) {
 				status.unchanged = true
 				status.reason = "config already complete with all settings"
@@ -175,46 +291,78 @@ discord:
 `,
 		researchRoot, externalRoot, provider, model)
 
-	if !fileExists {
+	content := []byte(config)
+	switch {
+	case !fileExists:
 		status.created = true
 		status.addedKeys = []string{"research_root", "external_root", "concurrency", "ai", "claude", "discord"}
-	} else {
+	case mode.overwrite:
+		status.overwritten = true
+	default:
+		merged, added, kept, err := mergeProjectConfig(existingData, content)
+		if err != nil {
+			return err
+		}
+		content = merged
 		status.merged = true
-		status.addedKeys = []string{"research_root", "external_root", "concurrency", "ai", "claude", "discord"}
+		status.addedKeys = added
+		status.keptKeys = kept
 	}
 
-	if err := os.WriteFile(configFile, []byte(config), 0o644); err != nil {
+	if err := os.WriteFile(configFile, content, 0o644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
 	addToGitignore := promptForConfirmation(scanner, "Add .arc/ to .gitignore?", true)
 	if addToGitignore {
-		if err := addToGitignoreFile(); err != nil {
+		added, err := addToGitignoreFile(log)
+		if err != nil {
 			return fmt.Errorf("failed to update .gitignore: %w", err)
 		}
-		status.gitignoreAdded = true
+		status.gitignoreAdded = added
+		status.gitignoreNoop = !added
 	}
 
 	return nil
 }
 
-func runScaffoldProject(gitignore, force bool, status *projectStatus) error {
+func runScaffoldProject(template string, gitignore bool, mode projectWriteMode, status *projectStatus, log *slog.Logger) error {
 	arcDir := ".arc"
 	configFile := filepath.Join(arcDir, "config.yaml")
 	status.configPath = configFile
-
-	if _, err := os.Stat(configFile); err == nil && !force {
-		status.unchanged = true
-		status.reason = "scaffold already exists (use --force to regenerate)"
-		return nil
+	status.template = template
+
+	_, err := os.Stat(configFile)
+	exists := err == nil
+	if exists && !mode.overwrite {
+		// The commented-out scaffold sets no keys, so only a template
+		// has anything to merge.
+		if !mode.merge || template == "" {
+			status.unchanged = true
+			status.reason = "scaffold already exists (use --overwrite to regenerate)"
+			return nil
+		}
+		return mergeProjectTemplate(configFile, template, gitignore, status, log)
 	}
 
-	status.created = true
+	if exists {
+		status.overwritten = true
+	} else {
+		status.created = true
+	}
 
 	if err := os.MkdirAll(arcDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create %s: %w", arcDir, err)
 	}
 
+	if template != "" {
+		content, err := loadProjectTemplate(template)
+		if err != nil {
+			return err
+		}
+		return writeProjectScaffold(configFile, content, gitignore, status, log)
+	}
+
 	scaffold := `# Arc Project Configuration Scaffold
 # Uncomment and customize the settings below to override global defaults.
 # See ~/.config/arc/config.yaml for global configuration.
@@ -243,17 +391,52 @@ func runScaffoldProject(gitignore, force bool, status *projectStatus) error {
 #   default_webhook: ""
 `
 
-	if err := os.WriteFile(configFile, []byte(scaffold), 0o644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+	return writeProjectScaffold(configFile, []byte(scaffold), gitignore, status, log)
+}
+
+// mergeProjectTemplate adds the template's keys that configFile lacks.
+func mergeProjectTemplate(configFile, template string, gitignore bool, status *projectStatus, log *slog.Logger) error {
+	existing, err := os.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	content, err := loadProjectTemplate(template)
+	if err != nil {
+		return err
+	}
+	merged, added, kept, err := mergeProjectConfig(existing, content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", configFile, err)
+	}
+	status.keptKeys = kept
+	log.Debug("merge project template", "path", configFile, "template", template, "added", len(added), "kept", len(kept))
+	if len(added) == 0 {
+		status.unchanged = true
+		status.reason = "config already sets every key in the " + template + " template"
+		return updateProjectGitignore(gitignore, status, log)
 	}
+	status.merged = true
+	status.addedKeys = added
+	return writeProjectScaffold(configFile, merged, gitignore, status, log)
+}
 
-	if gitignore {
-		if err := addToGitignoreFile(); err != nil {
-			return fmt.Errorf("failed to update .gitignore: %w", err)
-		}
-		status.gitignoreAdded = true
+func writeProjectScaffold(configFile string, content []byte, gitignore bool, status *projectStatus, log *slog.Logger) error {
+	if err := os.WriteFile(configFile, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
 	}
+	return updateProjectGitignore(gitignore, status, log)
+}
 
+func updateProjectGitignore(gitignore bool, status *projectStatus, log *slog.Logger) error {
+	if !gitignore {
+		return nil
+	}
+	added, err := addToGitignoreFile(log)
+	if err != nil {
+		return fmt.Errorf("failed to update .gitignore: %w", err)
+	}
+	status.gitignoreAdded = added
+	status.gitignoreNoop = !added
 	return nil
 }
 
@@ -262,10 +445,22 @@ func reportProjectStatus(cmd *cobra.Command, status projectStatus) {
 	fmt.Fprintln(cmd.OutOrStdout(), "=== Project Configuration Status ===")
 	fmt.Fprintln(cmd.OutOrStdout())
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Config file: %s\n\n", status.configPath)
+	fmt.Fprintf(cmd.OutOrStdout(), "Config file: %s\n", status.configPath)
+	if status.template != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "Template: %s\n", status.template)
+	}
+	if len(status.detected) > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Detected: %s\n", strings.Join(status.detected, ", "))
+	}
+	if status.detectNote != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "Detection: ambiguous (%s), using minimal\n", status.detectNote)
+	}
+	fmt.Fprintln(cmd.OutOrStdout())
 
 	if status.created {
 		fmt.Fprintln(cmd.OutOrStdout(), "CREATED - New project configuration file")
+	} else if status.overwritten {
+		fmt.Fprintln(cmd.OutOrStdout(), "OVERWRITTEN - Replaced the existing configuration")
 	} else if status.merged {
 		fmt.Fprintln(cmd.OutOrStdout(), "MERGED - Updated with new settings")
 		if len(status.addedKeys) > 0 {
@@ -279,15 +474,35 @@ func reportProjectStatus(cmd *cobra.Command, status projectStatus) {
 		fmt.Fprintln(cmd.OutOrStdout())
 		fmt.Fprintln(cmd.OutOrStdout(), "  Why: Config file already exists with all settings")
 		fmt.Fprintln(cmd.OutOrStdout(), "  To update: Edit .arc/config.yaml manually")
-		fmt.Fprintln(cmd.OutOrStdout(), "  To replace: Run with --force flag")
+		fmt.Fprintln(cmd.OutOrStdout(), "  To replace: Run with --overwrite flag")
+	}
+	if (status.merged || status.unchanged) && len(status.keptKeys) > 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "  Left untouched (already set):")
+		for _, key := range status.keptKeys {
+			fmt.Fprintf(cmd.OutOrStdout(), "    - %s\n", key)
+		}
+	}
+
+	if len(status.layoutCreated) > 0 || len(status.layoutSkipped) > 0 {
+		fmt.Fprintln(cmd.OutOrStdout())
+		fmt.Fprintln(cmd.OutOrStdout(), "Layout:")
+		for _, p := range status.layoutCreated {
+			fmt.Fprintf(cmd.OutOrStdout(), "  CREATED %s\n", p)
+		}
+		for _, p := range status.layoutSkipped {
+			fmt.Fprintf(cmd.OutOrStdout(), "  SKIPPED %s (already exists)\n", p)
+		}
 	}
 
 	if status.gitignoreAdded {
 		fmt.Fprintln(cmd.OutOrStdout())
 		fmt.Fprintln(cmd.OutOrStdout(), ".gitignore - Added .arc/ entry")
+	} else if status.gitignoreNoop {
+		fmt.Fprintln(cmd.OutOrStdout())
+		fmt.Fprintln(cmd.OutOrStdout(), ".gitignore - .arc/ already ignored")
 	}
 
-	if status.created || status.merged {
+	if status.created || status.merged || status.overwritten {
 		fmt.Fprintln(cmd.OutOrStdout())
 		fmt.Fprintln(cmd.OutOrStdout(), "Next steps:")
 		fmt.Fprintln(cmd.OutOrStdout(), "  - Edit .arc/config.yaml to customize settings")
@@ -295,6 +510,31 @@ func reportProjectStatus(cmd *cobra.Command, status projectStatus) {
 	}
 }
 
+// projectTemplateNames lists the built-in project templates.
+func projectTemplateNames() []string {
+	entries, _ := fs.ReadDir(projectTemplates, "templates/project")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadProjectTemplate returns the named template after checking it parses as
+// YAML.
+func loadProjectTemplate(name string) ([]byte, error) {
+	data, err := projectTemplates.ReadFile("templates/project/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown template %q (valid: %s)", name, strings.Join(projectTemplateNames(), ", "))
+	}
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("template %s is not valid YAML: %w", name, err)
+	}
+	return data, nil
+}
+
 func getOrPrompt(scanner *bufio.Scanner, existing map[string]interface{}, key, prompt, defaultVal string) string {
 	if existing != nil {
 		if val, ok := existing[key]; ok {
@@ -358,23 +598,3 @@ func promptForConfirmation(scanner *bufio.Scanner, prompt string, defaultVal boo
 	}
 	return defaultVal
 }
-
-func addToGitignoreFile() error {
-	gitignorePath := ".gitignore"
-	content := ""
-
-	if data, err := os.ReadFile(gitignorePath); err == nil {
-		content = string(data)
-	}
-
-	if strings.Contains(content, ".arc/") {
-		return nil
-	}
-
-	entry := ".arc/\n"
-	if !strings.HasSuffix(content, "\n") && content != "" {
-		entry = "\n" + entry
-	}
-
-	return os.WriteFile(gitignorePath, []byte(content+entry), 0o644)
-}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectMarkers are the files --detect looks for, in report order.
+var projectMarkers = []string{"go.mod", "package.json", "Cargo.toml", "Dockerfile"}
+
+// projectDetection is the outcome of inspecting a directory for --detect.
+type projectDetection struct {
+	template string
+	markers  []string
+	note     string
+}
+
+// detectProjectTemplate picks a template from the project files in dir. A
+// Dockerfile means something is deployed, so it selects service; a single
+// language manifest selects library. Anything else is ambiguous and falls
+// back to minimal with a note explaining why.
+func detectProjectTemplate(dir string) projectDetection {
+	var d projectDetection
+	languages := 0
+	docker := false
+	for _, name := range projectMarkers {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			continue
+		}
+		d.markers = append(d.markers, name)
+		if name == "Dockerfile" {
+			docker = true
+		} else {
+			languages++
+		}
+	}
+
+	switch {
+	case docker:
+		d.template = "service"
+	case languages == 1:
+		d.template = "library"
+	case languages > 1:
+		d.template = "minimal"
+		d.note = "found " + strings.Join(d.markers, ", ") + "; pick one with --template"
+	default:
+		d.template = "minimal"
+		d.note = "no " + strings.Join(projectMarkers, ", ") + " found"
+	}
+	return d
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeProjectConfig adds the keys of defaults that existing lacks, recursing
+// into nested mappings, and returns the result along with the dotted paths it
+// added and those it kept because existing already set them. Values and
+// comments already in existing are preserved; a key whose value is not a
+// mapping on either side is never descended into.
+func mergeProjectConfig(existing, defaults []byte) ([]byte, []string, []string, error) {
+	var dst, src yaml.Node
+	if err := yaml.Unmarshal(existing, &dst); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse existing config: %w", err)
+	}
+	if err := yaml.Unmarshal(defaults, &src); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(src.Content) == 0 {
+		return existing, nil, nil, nil
+	}
+	if len(dst.Content) == 0 {
+		// Only comments so far (e.g. the scaffold): keep them and append
+		// the defaults below.
+		var added []string
+		for i := 0; i+1 < len(src.Content[0].Content); i += 2 {
+			added = append(added, src.Content[0].Content[i].Value)
+		}
+		out := append(bytes.TrimRight(existing, "\n"), '\n')
+		if len(bytes.TrimSpace(existing)) > 0 {
+			out = append(out, '\n')
+		}
+		return append(out, defaults...), added, nil, nil
+	}
+	if dst.Content[0].Kind != yaml.MappingNode || src.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, nil, fmt.Errorf("existing config is not a YAML mapping")
+	}
+
+	var added, kept []string
+	mergeMapping(dst.Content[0], src.Content[0], "", &added, &kept)
+	if len(added) == 0 {
+		return existing, nil, kept, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&dst); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, nil, err
+	}
+	return buf.Bytes(), added, kept, nil
+}
+
+func mergeMapping(dst, src *yaml.Node, path string, added, kept *[]string) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		child := joinConfigPath(path, key.Value)
+		existing := mappingValue(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+			*added = append(*added, child)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeMapping(existing, value, child, added, kept)
+		default:
+			*kept = append(*kept, child)
+		}
+	}
+}
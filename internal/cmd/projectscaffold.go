@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// projectLayout is the placeholder content --scaffold creates beside
+// .arc/config.yaml, so every project starts from the same structure. The
+// directories hold a README each because git does not track empty ones.
+var projectLayout = []struct {
+	path    string
+	content string
+}{
+	{filepath.Join(".arc", "hooks", "README.md"), `# Arc hooks
+
+Executable scripts in this directory are project hooks. Name each one after
+the event it handles and commit it with the project so the whole team runs
+the same hooks.
+`},
+	{filepath.Join(".arc", "templates", "README.md"), `# Arc templates
+
+Templates placed here are shared by everyone working on this project and take
+precedence over the ones in ~/.config/arc.
+`},
+}
+
+// scaffoldProjectLayout writes the projectLayout placeholders, leaving any
+// that already exist alone unless overwrite is set.
+func scaffoldProjectLayout(overwrite bool, status *projectStatus, log *slog.Logger) error {
+	for _, f := range projectLayout {
+		_, err := os.Stat(f.path)
+		exists := err == nil
+		log.Debug("stat scaffold file", "path", f.path, "exists", exists)
+		if exists && !overwrite {
+			status.layoutSkipped = append(status.layoutSkipped, f.path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(f.path), err)
+		}
+		if err := os.WriteFile(f.path, []byte(f.content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+		status.layoutCreated = append(status.layoutCreated, f.path)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// rcTxn tracks the RC files changed during one install run so that, if any
+// RC edit fails, the files already changed can be put back. A nil *rcTxn
+// applies edits without tracking them.
+type rcTxn struct {
+	mu     sync.Mutex
+	edits  []rcEdit
+	failed bool
+}
+
+// rcEdit is the state of an RC file before the run first touched it.
+type rcEdit struct {
+	path    string
+	data    []byte
+	mode    fs.FileMode
+	existed bool
+}
+
+// snapshot records path's current contents the first time it is edited.
+func (t *rcTxn) snapshot(path string) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, e := range t.edits {
+		if e.path == path {
+			return nil
+		}
+	}
+
+	edit := rcEdit{path: path, mode: 0o644}
+	info, err := os.Stat(path)
+	if err == nil {
+		if edit.data, err = os.ReadFile(path); err != nil {
+			return err
+		}
+		edit.mode = info.Mode().Perm()
+		edit.existed = true
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	t.edits = append(t.edits, edit)
+	return nil
+}
+
+// upsert is upsertRCBlock recorded in the transaction.
+func (t *rcTxn) upsert(path, block string, replace bool, keepBackups int, log *slog.Logger) error {
+	if err := t.snapshot(path); err != nil {
+		return err
+	}
+	return upsertRCBlock(path, block, replace, keepBackups, log)
+}
+
+// remove is removeRCBlock recorded in the transaction.
+func (t *rcTxn) remove(path string, dryRun bool, keepBackups int, log *slog.Logger) (bool, error) {
+	if !dryRun {
+		if err := t.snapshot(path); err != nil {
+			return false, err
+		}
+	}
+	return removeRCBlock(path, dryRun, keepBackups, log)
+}
+
+// fail marks the run as needing a rollback.
+func (t *rcTxn) fail() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.failed = true
+	t.mu.Unlock()
+}
+
+// rollback restores every recorded file, newest edit first, if the run
+// failed. It returns the paths restored; files the run created are removed.
+func (t *rcTxn) rollback(log *slog.Logger) ([]string, error) {
+	if t == nil {
+		return nil, nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.failed {
+		return nil, nil
+	}
+
+	var restored []string
+	var errs []error
+	for i := len(t.edits) - 1; i >= 0; i-- {
+		e := t.edits[i]
+		var err error
+		if e.existed {
+			err = os.WriteFile(e.path, e.data, e.mode)
+		} else {
+			err = os.Remove(e.path)
+			if errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		}
+		log.Debug("roll back RC file", "path", e.path, "existed", e.existed, "err", err)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		restored = append(restored, e.path)
+	}
+	t.edits = nil
+	return restored, errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newReinstallCmd() *cobra.Command {
+	var all, dryRun, noColor, strict, yes, strictConfirm bool
+
+	cmd := &cobra.Command{
+		Use:   "reinstall",
+		Short: "Regenerate installed shell completions after an upgrade",
+		Long: `Regenerate arc completions for every shell that already has them.
+
+This is the post-upgrade shortcut for arc-init shell --force --write-rc: each
+shell with an arc completion file at its managed path is regenerated in place
+and its RC block is ensured. Shells without arc completions are left alone
+unless --all is given. As with arc-init shell, RC changes are confirmed on a
+terminal unless --yes is given, and the exit statuses are the same.`,
+		Example: `  arc-init reinstall
+  arc-init reinstall --all
+  arc-init reinstall --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := shellOptions{
+				force:       true,
+				writeRC:     true,
+				dryRun:      dryRun,
+				noColor:     noColor,
+				keepBackups: defaultKeepBackups,
+				timeout:     defaultCommandTimeout,
+				log:         loggerFrom(cmd.Context()),
+				paths:       pathsFrom(cmd.Context()),
+			}
+
+			selected := make(map[string]bool)
+			for _, sh := range installedCompletionShells(opts) {
+				selected[sh] = true
+			}
+			if all {
+				for _, sh := range allShells(opts.paths.goos, os.Getenv("SHELL")) {
+					selected[sh] = true
+				}
+			}
+
+			var shells []string
+			for _, sh := range supportedShells {
+				if selected[sh] {
+					shells = append(shells, sh)
+				}
+			}
+			if len(shells) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No arc completions are installed. Run arc-init shell first, or pass --all.")
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Reinstalling completions for: %s\n", strings.Join(shells, ", "))
+
+			if !yes {
+				if err := opts.setupConfirm(cmd, strictConfirm); err != nil {
+					return err
+				}
+			}
+			if !dryRun {
+				unlock, err := holdShellState(cmd, opts)
+				if err != nil {
+					return err
+				}
+				defer unlock()
+			}
+			statuses, err := installShellStatuses(InstallOptions{Root: cmd.Root(), Shells: shells, Stderr: cmd.ErrOrStderr(), shell: &opts})
+			reportShellStatus(cmd, statuses, opts)
+			if exit := shellExitStatus(cmd, statuses, strict); exit != nil {
+				return exit
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Also install for shells that have no arc completions yet")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be written without touching the filesystem")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output (also honors NO_COLOR)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Change RC files without asking (prompts appear only on a terminal)")
+	cmd.Flags().BoolVar(&strictConfirm, "strict-confirm", false, "Refuse to change RC files without --yes when no terminal is available to ask")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit with status 3 when nothing was written or removed")
+
+	return cmd
+}
+
+// installedCompletionShells returns the shells that have a completion file at
+// their managed path.
+func installedCompletionShells(opts shellOptions) []string {
+	var shells []string
+	for _, sh := range supportedShells {
+		path, err := completionPath(sh, opts)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			shells = append(shells, sh)
+		}
+	}
+	return shells
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReinstallFailedWriteExitsNonZero(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "no-data-home"))
+	t.Setenv("MSYSTEM", "")
+	path, err := completionPath("bash", shellOptions{paths: newPathContext("")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# arc-init v0.0.1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	renameFile = func(string, string) error { return errors.New("rename failed") }
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	root := NewRootCmd()
+	root.SetArgs([]string{"reinstall"})
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	err = root.Execute()
+	var exit *ExitError
+	if !errors.As(err, &exit) || exit.Code != ExitShellFailed {
+		t.Fatalf("reinstall error = %v, want exit status %d", err, ExitShellFailed)
+	}
+}
+
+func TestReinstallStrictConfirmRefusesWithoutTerminal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	root := NewRootCmd()
+	root.SetArgs([]string{"reinstall", "--all", "--strict-confirm"})
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "without confirmation") {
+		t.Fatalf("reinstall --strict-confirm error = %v, want a confirmation refusal", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".bashrc")); err == nil {
+		t.Error(".bashrc written despite the refusal")
+	}
+}
@@ -7,27 +7,64 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// defaultCommandName is the name the arc-init binary is installed under.
+const defaultCommandName = "arc-init"
+
+// completionCommandName is the command completions bind to by default: users
+// type arc, which runs arc-init's commands as "arc init".
+const completionCommandName = "arc"
+
 // NewRootCmd creates the root command for arc-init.
 func NewRootCmd() *cobra.Command {
+	var (
+		verbose    bool
+		configHome string
+	)
+
 	cmd := &cobra.Command{
-		Use:   "arc-init",
+		Use:   defaultCommandName,
 		Short: "Initialize arc components",
 		Long: `Initialize various arc components.
 
 This command group provides setup wizards for different arc features:
   - system: Initialize global arc configuration (~/.config/arc/)
   - project: Initialize project-local configuration (.arc/config.yaml)
-  - shell: Initialize shell completions (bash, zsh, fish, PowerShell)`,
+  - shell: Initialize shell completions (bash, zsh, fish, PowerShell, nushell, elvish, xonsh, tcsh)
+  - reinstall: Regenerate installed shell completions after an upgrade
+  - doctor: Diagnose shell completion setup
+  - status: List everything arc-init has installed
+  - validate: Check a project config against the config schema
+  - completion: Print a completion script to stdout
+  - version: Print build information`,
 		Example: `  arc init system --interactive
   arc init project --interactive
   arc init project --scaffold --gitignore
-  arc init shell`,
+  arc init shell
+  arc init doctor`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			home, err := expandPath("--config-home", configHome)
+			if err != nil {
+				return err
+			}
+			ctx := withLogger(cmd.Context(), newLogger(cmd.ErrOrStderr(), verbose))
+			cmd.SetContext(withPaths(ctx, newPathContext(home)))
+			return nil
+		},
 	}
 
+	cmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable debug logging")
+	cmd.PersistentFlags().StringVar(&configHome, "config-home", "", "Base config directory used instead of $XDG_CONFIG_HOME or ~/.config")
+
 	cmd.AddCommand(
 		newSystemCmd(),
 		newProjectCmd(),
 		newShellCmd(),
+		newReinstallCmd(),
+		newDoctorCmd(),
+		newStatusCmd(),
+		newValidateCmd(),
+		newCompletionCmd(),
+		newVersionCmd(),
 	)
 
 	return cmd
@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Each self-test script loads the completion file the way the shell's RC
+// block would (after bash-completion, for bash), then prints the candidates
+// offered for "<command> " one per line. The shell's own completion
+// machinery produces them where it can be driven without a terminal (bash,
+// fish, PowerShell); zsh and tcsh can only confirm the completion
+// registered, after which the script asks __complete directly.
+const (
+	bashSelfTest = `for f in /usr/share/bash-completion/bash_completion /etc/bash_completion \
+	"${HOMEBREW_PREFIX:-/opt/homebrew}/etc/profile.d/bash_completion.sh" /usr/local/etc/profile.d/bash_completion.sh; do
+	[ -r "$f" ] && . "$f" && break
+done
+declare -F _get_comp_words_by_ref >/dev/null || { echo "bash-completion is not installed; the bash completion needs it" >&2; exit 1; }
+source "$1" || exit 1
+fn=$(complete -p "$2" 2>/dev/null | sed -n 's/.*-F \([^ ]*\).*/\1/p')
+[ -n "$fn" ] || { echo "no completion registered for $2" >&2; exit 1; }
+COMP_LINE="$2 "; COMP_POINT=${#COMP_LINE}; COMP_WORDS=("$2" ""); COMP_CWORD=1
+"$fn" "$2" "" "$2"
+printf '%s\n' "${COMPREPLY[@]}"`
+
+	zshSelfTest = `fpath=("$1" $fpath)
+autoload -Uz compinit && compinit -u -D
+(( $+_comps[$2] )) || { print -u2 "no completion registered for $2"; exit 1 }
+"$2" __complete "" 2>/dev/null | grep -v '^:' | cut -f1`
+
+	fishSelfTest = `source $argv[1]; or exit 1
+complete --do-complete "$argv[2] " | cut -f1`
+
+	psSelfTest = `param($path, $command)
+. $path
+$line = "$command "
+(TabExpansion2 -inputScript $line -cursorColumn $line.Length).CompletionMatches | ForEach-Object { $_.CompletionText }`
+
+	tcshSelfTest = `source "$ARC_SELFTEST_FILE" && complete "$ARC_SELFTEST_COMMAND" | grep -q . && sh -c '"$0" __complete "" 2>/dev/null | grep -v "^:" | cut -f1' "$ARC_SELFTEST_COMMAND"`
+)
+
+// selfTestCommand returns the command that runs the self-test for shell
+// against the completion file at path, or nil when shell has no self-test or
+// its binary is not installed. overrides are the --shell-path executables.
+func selfTestCommand(ctx context.Context, shell, path, command string, overrides map[string]string) *exec.Cmd {
+	bin := shellBinary(shell, overrides)
+	if bin == "" {
+		return nil
+	}
+	switch shell {
+	case "bash":
+		return exec.CommandContext(ctx, bin, "--noprofile", "--norc", "-c", bashSelfTest, "bash", path, command)
+	case "zsh":
+		return exec.CommandContext(ctx, bin, "-f", "-c", zshSelfTest, "zsh", filepath.Dir(path), command)
+	case "fish":
+		return exec.CommandContext(ctx, bin, "--no-config", "-c", fishSelfTest, path, command)
+	case "powershell":
+		script := "& {" + psSelfTest + "} " + psQuote(path) + " " + psQuote(command)
+		return exec.CommandContext(ctx, bin, "-NoProfile", "-NonInteractive", "-Command", script)
+	case "tcsh":
+		c := exec.CommandContext(ctx, bin, "-f", "-c", tcshSelfTest)
+		c.Env = append(os.Environ(), "ARC_SELFTEST_FILE="+path, "ARC_SELFTEST_COMMAND="+command)
+		return c
+	}
+	return nil
+}
+
+// psQuote returns s as a PowerShell single-quoted string, in which only a
+// quote needs escaping (by doubling it).
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// selfTestCompletion loads the installed completion for shell in a fresh,
+// non-interactive shell and checks that completing "<command> " offers
+// candidates. It returns the number of candidates, or -1 with a nil error
+// when the test had to be skipped.
+func selfTestCompletion(shell, path string, opts shellOptions) (int, error) {
+	command, timeout := opts.command(), opts.commandTimeout()
+	// The completion calls the binary by its command name, which need not be
+	// on PATH (e.g. a fresh build); expose this executable under that name.
+	shim, err := os.MkdirTemp("", "arc-selftest-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(shim)
+	if exe, err := os.Executable(); err == nil {
+		_ = os.Symlink(exe, filepath.Join(shim, command))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	c := selfTestCommand(ctx, shell, path, command, opts.shellPaths)
+	if c == nil {
+		return -1, nil
+	}
+	if c.Env == nil {
+		c.Env = os.Environ()
+	}
+	c.Env = append(c.Env, "PATH="+shim+string(os.PathListSeparator)+os.Getenv("PATH"))
+	c.WaitDelay = 100 * time.Millisecond
+	var stdout, stderr bytes.Buffer
+	c.Stdout, c.Stderr = &stdout, &stderr
+
+	err = c.Run()
+	if ctx.Err() != nil {
+		return 0, fmt.Errorf("%w: no result after %s", ErrSelfTest, timeout)
+	}
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return 0, fmt.Errorf("%w: %s", ErrSelfTest, msg)
+	}
+	n := 0
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("%w: completing %q offered no candidates", ErrSelfTest, command+" ")
+	}
+	return n, nil
+}
+
+// runSelfTest records the --self-test result for the completion in status.
+func runSelfTest(status *shellStatus, shell string, opts shellOptions) error {
+	n, err := selfTestCompletion(shell, status.path, opts)
+	opts.logger().Debug("self-test", "shell", shell, "candidates", n, "err", err)
+	switch {
+	case err != nil:
+		status.selfTest = "failed"
+		return err
+	case n < 0:
+		if shellBinary(shell, opts.shellPaths) == "" {
+			status.selfTest = "skipped (" + shell + " is not installed)"
+		} else {
+			status.selfTest = "skipped (no self-test for " + shell + ")"
+		}
+	default:
+		status.selfTest = fmt.Sprintf("passed (%d candidates)", n)
+	}
+	return nil
+}
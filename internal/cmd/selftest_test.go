@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPowerShellSelfTestQuotesArguments(t *testing.T) {
+	c := selfTestCommand(context.Background(), "powershell", "/tmp/o'brien/arc.ps1", "arc'; Remove-Item x; '", map[string]string{"powershell": "pwsh"})
+	if c == nil {
+		t.Fatal("no self-test command for powershell")
+	}
+	script := c.Args[len(c.Args)-1]
+	want := "} '/tmp/o''brien/arc.ps1' 'arc''; Remove-Item x; '''"
+	if !strings.HasSuffix(script, want) {
+		t.Errorf("script ends %q, want suffix %q", script[strings.LastIndex(script, "}"):], want)
+	}
+}
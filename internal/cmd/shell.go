@@ -4,13 +4,29 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-init/internal/blockedit"
 )
 
 type shellStatus struct {
@@ -20,113 +36,687 @@ type shellStatus struct {
 	rcWritten bool
 	rcSkipped bool
 	rcRemoved bool
+	dryRun    bool
+	path      string
+	rcPath    string
+	rcBlock   string
 	reason    string
+	rcReason  string
+	errs      []string
+	errCodes  []string
+	// failures are the errors behind errs, for InstallShells.
+	failures []error
+
+	validation        string
+	rcMigrated        bool
+	conflicts         []string
+	completionRemoved bool
+	rcStrategy        string
+	profilePath       string
+	// profileRemoved marks profilePath as uninstalled rather than added.
+	profileRemoved bool
+	rcRolledBack   bool
+	aliases        []string
+	aliasPaths     []string
+	// rcDiff is the unified diff a dry run would apply to rcPath.
+	rcDiff string
+	// rcUpdated marks an outdated block rewritten in place by --force or
+	// --force-rc.
+	rcUpdated bool
+	// rcUnchanged marks an RC block left alone because it already matched.
+	rcUnchanged bool
+	// selfTest is the --self-test result: "passed (N candidates)",
+	// "failed", or "skipped (...)".
+	selfTest string
+	// shellMissing marks a shell skipped by --skip-missing because its
+	// binary is not on PATH.
+	shellMissing bool
+	// rcKind is "login" or "interactive" for bash and zsh RC files.
+	rcKind string
+	// unchanged marks a completion file left alone because it already held
+	// the generated content.
+	unchanged bool
+	// descriptions is "on" or "off" for shells whose candidates can carry
+	// descriptions, and empty otherwise.
+	descriptions string
+	// backups are the copies of overwritten completion files saved by
+	// --force.
+	backups []string
+	// commandWarning explains why the completion may not fire for the
+	// command users type; see checkCommandBinding.
+	commandWarning string
+	// backupsRemoved are the RC backups deleted by --clean-backups.
+	backupsRemoved []string
+}
+
+// shellStatusJSON is the --json representation of a shellStatus.
+type shellStatusJSON struct {
+	Shell          string   `json:"shell"`
+	CompletionPath string   `json:"completion_path,omitempty"`
+	RCPath         string   `json:"rc_path,omitempty"`
+	Written        bool     `json:"written"`
+	Skipped        bool     `json:"skipped"`
+	Unchanged      bool     `json:"unchanged"`
+	ShellMissing   bool     `json:"shell_missing"`
+	RCWritten      bool     `json:"rc_written"`
+	RCSkipped      bool     `json:"rc_skipped"`
+	RCRemoved      bool     `json:"rc_removed"`
+	RCMigrated     bool     `json:"rc_migrated"`
+	RCRolledBack   bool     `json:"rc_rolled_back"`
+	RCUpdated      bool     `json:"rc_updated"`
+	Removed        bool     `json:"completion_removed"`
+	DryRun         bool     `json:"dry_run"`
+	Validation     string   `json:"validation,omitempty"`
+	SelfTest       string   `json:"self_test,omitempty"`
+	Conflicts      []string `json:"conflicts,omitempty"`
+	Reason         string   `json:"reason,omitempty"`
+	RCReason       string   `json:"rc_reason,omitempty"`
+	RCStrategy     string   `json:"rc_strategy,omitempty"`
+	RCTarget       string   `json:"rc_target,omitempty"`
+	Aliases        []string `json:"aliases,omitempty"`
+	AliasPaths     []string `json:"alias_paths,omitempty"`
+	RCDiff         string   `json:"rc_diff,omitempty"`
+	Descriptions   string   `json:"descriptions,omitempty"`
+	Backups        []string `json:"backups,omitempty"`
+	CommandWarning string   `json:"command_warning,omitempty"`
+	BackupsRemoved []string `json:"backups_removed,omitempty"`
+	Error          string   `json:"error,omitempty"`
+	ErrorCodes     []string `json:"error_codes,omitempty"`
+}
+
+func (s shellStatus) toJSON() shellStatusJSON {
+	return shellStatusJSON{
+		Shell:          s.shell,
+		CompletionPath: s.path,
+		RCPath:         s.rcPath,
+		Written:        s.written,
+		Skipped:        s.skipped,
+		Unchanged:      s.unchanged,
+		ShellMissing:   s.shellMissing,
+		RCWritten:      s.rcWritten,
+		RCSkipped:      s.rcSkipped,
+		RCRemoved:      s.rcRemoved,
+		RCMigrated:     s.rcMigrated,
+		RCRolledBack:   s.rcRolledBack,
+		RCUpdated:      s.rcUpdated,
+		Removed:        s.completionRemoved,
+		DryRun:         s.dryRun,
+		Validation:     s.validation,
+		SelfTest:       s.selfTest,
+		Conflicts:      s.conflicts,
+		Reason:         s.reason,
+		RCReason:       s.rcReason,
+		RCStrategy:     s.rcStrategy,
+		RCTarget:       s.rcKind,
+		Aliases:        s.aliases,
+		AliasPaths:     s.aliasPaths,
+		RCDiff:         s.rcDiff,
+		Descriptions:   s.descriptions,
+		Backups:        s.backups,
+		CommandWarning: s.commandWarning,
+		BackupsRemoved: s.backupsRemoved,
+		Error:          strings.Join(s.errs, "; "),
+		ErrorCodes:     s.errCodes,
+	}
+}
+
+// shellOptions carries the flags that affect where and how completion files
+// and RC blocks are written.
+type shellOptions struct {
+	force                bool
+	forceRC              bool
+	dryRun               bool
+	writeRC              bool
+	uninstallRC          bool
+	uninstall            bool
+	uninstallCompletions bool
+	check                bool
+	migrateRC            bool
+	jsonOutput           bool
+	noColor              bool
+	// onlyChanges is --report-only-changes: the text report leaves out
+	// shells where nothing was written or removed.
+	onlyChanges bool
+	log         *slog.Logger
+	paths       pathContext
+	outputDir   string
+	system      bool
+	restore     string
+	keepBackups int
+	// cleanBackups deletes RC backups after their block is uninstalled.
+	cleanBackups       bool
+	followSymlinks     bool
+	completionsOnly    bool
+	rcFile             string
+	rcOnly             bool
+	rcTxn              *rcTxn
+	homebrew           bool
+	aliases            []string
+	noDescriptions     bool
+	commandName        string
+	timeout            time.Duration
+	completionTemplate *template.Template
+	// selfTest loads each installed completion in a fresh shell to check
+	// that it offers candidates.
+	selfTest bool
+	// confirm asks before RC changes on a terminal; nil approves them.
+	confirm *rcConfirmer
+	// staticBash generates the bash script with the command tree baked in
+	// (--dynamic=false).
+	staticBash bool
+	// includeHidden bakes hidden commands into the static bash script.
+	includeHidden bool
+	// xdgData places bash, zsh, and fish completions under $XDG_DATA_HOME.
+	xdgData bool
+	// activeShell, from --shell, replaces detection of the current shell.
+	activeShell string
+	// forceDetect prefers the parent process over $SHELL when detecting the
+	// current shell, and skips the install cache.
+	forceDetect bool
+	// nix installs for home-manager: the --xdg-data layout with no RC
+	// edits, printing the home.nix settings to add instead.
+	nix bool
+	// skipMissing skips shells whose binary is not on PATH.
+	skipMissing bool
+	// rcTargetMode is --rc-target: "auto", "interactive", or "login".
+	rcTargetMode string
+	// shellPaths maps shells to the executables given with --shell-path.
+	shellPaths map[string]string
+	// scopedUninstall limits --uninstall to the manifest entries of the
+	// selected shells; it is unset under --all or when no shell was picked.
+	scopedUninstall bool
+}
+
+// rcPathFor returns the RC file for shell, honoring --rc-file for shells that
+// have RC integration.
+func (o shellOptions) rcPathFor(shell string) string {
+	path := o.paths.rcPathFor(shell)
+	if path == "" {
+		return ""
+	}
+	if o.rcFile != "" {
+		return o.rcFile
+	}
+	var forced string
+	switch o.rcTargetMode {
+	case "login":
+		forced = o.paths.loginRCPathFor(shell)
+	case "interactive":
+		forced = o.paths.interactiveRCPathFor(shell)
+	}
+	if forced != "" {
+		return forced
+	}
+	return path
+}
+
+// rcTargetKind reports whether the RC file for shell is read by login or
+// interactive shells, or "" for shells without that split or with --rc-file.
+func (o shellOptions) rcTargetKind(shell string) string {
+	if o.rcFile != "" || o.paths.loginRCPathFor(shell) == "" {
+		return ""
+	}
+	if o.rcPathFor(shell) == o.paths.interactiveRCPathFor(shell) {
+		return "interactive"
+	}
+	return "login"
+}
+
+// currentShell is the shell treated as active: --shell when given, else the
+// detected one.
+func (o shellOptions) currentShell() string {
+	if o.activeShell != "" {
+		return o.activeShell
+	}
+	if o.forceDetect {
+		return detectShellFrom(parentProcessName(o.commandTimeout()), func() string { return os.Getenv("SHELL") })
+	}
+	return detectShell(o.commandTimeout())
+}
+
+// commandTimeout bounds the external commands a run starts: --timeout, or
+// defaultCommandTimeout for options built without one.
+func (o shellOptions) commandTimeout() time.Duration {
+	if o.timeout > 0 {
+		return o.timeout
+	}
+	return defaultCommandTimeout
+}
+
+// command returns the command name completions bind to: --command-name, or
+// arc.
+func (o shellOptions) command() string {
+	if o.commandName != "" {
+		return o.commandName
+	}
+	return completionCommandName
+}
+
+// logger returns the configured logger, discarding output when none is set.
+func (o shellOptions) logger() *slog.Logger {
+	if o.log == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return o.log
 }
 
 func newShellCmd() *cobra.Command {
-	var bash, zsh, fish, powershell bool
-	var force bool
-	var writeRC bool
-	var uninstallRC bool
-	var all bool
+	var bash, zsh, fish, powershell, nushell, elvish, xonsh, tcsh bool
+	var all, interactive, save, list bool
+	var shellPaths []string
+	var profile, bundle, fromBundle, templateFile, summaryFile, emitTo, combined string
+	var refresh, strict, yes, strictConfirm, plan, printBlock, pruneOrphans, watch bool
+	dynamic := true
+	var opts shellOptions
 
 	cmd := &cobra.Command{
 		Use:   "shell",
 		Short: "Initialize shell completions",
 		Long: `Set up shell completions for arc commands.
 
-Installs completion scripts for bash, zsh, fish, and PowerShell.
-By default, detects your current shell from the SHELL environment variable.
+Installs completion scripts for bash, zsh, fish, PowerShell, nushell, elvish,
+xonsh, and tcsh, and with --write-rc adds a marked block to each shell's RC
+file that loads them. The scripts bind to arc (see --command-name) and by
+default ask the hidden __complete command for candidates, so new subcommands
+complete without regenerating anything.
+
+Shells are chosen in this order of precedence:
+  1. Shell flags (--bash, --zsh, ...), or --interactive / --all
+  2. shell.install in ~/.config/arc/config.yaml (see --save)
+  3. The current shell: --shell, else $SHELL or the parent process
+
+Running it again is safe. Existing completion files are kept unless --force
+is given, RC blocks are added once, and an outdated block is rewritten in
+place only with --force or --force-rc. RC files are backed up before each
+change (see --keep-backups and --restore) and, on a terminal, every RC change
+is shown and confirmed first unless --yes is given. Everything written is
+recorded in ~/.config/arc/shell-manifest.json, so --uninstall removes exactly
+that. --dry-run and --plan show what a run would do without changing anything.
+
+A shell that fails is reported and the others still run. The exit status is:
+  0  every selected shell succeeded (or was already set up)
+  1  invalid flags or an error that stopped the run
+  2  at least one shell failed; the others were still installed
+  3  with --strict only: nothing was written or removed
+  4  the config directory (~/.config/arc, or --config-home) is not writable
 
-Idempotent: Running multiple times is safe. Existing files are not overwritten
-unless --force is used. RC file blocks are added once and not duplicated.`,
+The README describes where each shell's files go and the less common flags.`,
 		Example: `  arc-init shell
+  arc-init shell --list-shells
   arc-init shell --all
+  arc-init shell --interactive
+  arc-init shell --bash --zsh --save
   arc-init shell --bash --zsh
   arc-init shell --write-rc
-  arc-init shell --uninstall-rc`,
+  arc-init shell --write-rc --force-rc
+  arc-init shell --uninstall-rc
+  arc-init shell --all --rc-only
+  arc-init shell --bash --write-rc --rc-file ~/.config/bash/bashrc
+  arc-init shell --bash --uninstall-completions
+  arc-init shell --uninstall
+  arc-init shell --bash --restore
+  arc-init shell --bash --restore=20250101-120000
+  arc-init shell --all --write-rc --dry-run
+  arc-init shell --all --check
+  sudo arc-init shell --bash --zsh --fish --system
+  arc-init shell --bash --zsh --fish --homebrew
+  arc-init shell --bash --zsh --fish --nix
+  arc-init shell --bash --fish --alias a --force
+  arc-init shell --all --bundle arc-completions.tar.gz
+  arc-init shell --install-bundle arc-completions.tar.gz --force
+  arc-init shell --bash --output-dir /usr/local/share/bash-completion/completions`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if !bash && !zsh && !fish && !powershell {
-				if all {
-					bash, zsh, fish = true, true, true
-				} else {
-					sh := detectShell()
-					switch sh {
-					case "bash":
-						bash = true
-					case "zsh":
-						zsh = true
-					case "fish":
-						fish = true
-					case "powershell":
-						powershell = true
-					default:
-						bash, zsh = true, true
-					}
+			opts.log = loggerFrom(cmd.Context())
+			opts.paths = pathsFrom(cmd.Context())
+			var err error
+			if opts.outputDir, err = expandPath("--output-dir", opts.outputDir); err != nil {
+				return err
+			}
+			if opts.rcFile, err = expandPath("--rc-file", opts.rcFile); err != nil {
+				return err
+			}
+			if summaryFile, err = expandPath("--summary-file", summaryFile); err != nil {
+				return err
+			}
+			if emitTo, err = expandPath("--emit-to", emitTo); err != nil {
+				return err
+			}
+			if combined, err = expandPath("--combined", combined); err != nil {
+				return err
+			}
+			switch opts.rcTargetMode {
+			case "auto", "interactive", "login":
+			default:
+				return fmt.Errorf("invalid --rc-target %q: use interactive, login, or auto", opts.rcTargetMode)
+			}
+			opts.staticBash = !dynamic
+			if opts.includeHidden && !opts.staticBash {
+				return fmt.Errorf("--include-hidden-commands needs --dynamic=false: dynamic scripts ask the binary for candidates, which never offers hidden commands")
+			}
+			if opts.timeout <= 0 {
+				return fmt.Errorf("--timeout must be positive, got %s", opts.timeout)
+			}
+			if opts.shellPaths, err = parseShellPaths(shellPaths); err != nil {
+				return err
+			}
+			if profile != "" {
+				var err error
+				if opts.paths, err = opts.paths.withProfile(profile); err != nil {
+					return err
 				}
 			}
 
-			var statuses []shellStatus
-			root := cmd.Root()
+			if opts.commandName != "" && !aliasNamePattern.MatchString(opts.commandName) {
+				return fmt.Errorf("invalid --command-name %q: use letters, digits, '.', '_', '+', or '-'", opts.commandName)
+			}
+			if err := validateAliases(opts.aliases, opts.command()); err != nil {
+				return err
+			}
+			if templateFile != "" {
+				var err error
+				if opts.completionTemplate, err = parseCompletionTemplate(templateFile); err != nil {
+					return err
+				}
+			}
+			if opts.completionsOnly && opts.rcOnly {
+				return fmt.Errorf("cannot use both --completions-only and --rc-only")
+			}
+			if opts.cleanBackups && !opts.uninstallRC && !opts.uninstall {
+				return fmt.Errorf("--clean-backups only applies with --uninstall-rc or --uninstall")
+			}
+			if opts.rcOnly && opts.uninstallCompletions {
+				return fmt.Errorf("cannot use --rc-only with --uninstall-completions")
+			}
+			if opts.rcOnly && opts.system {
+				return fmt.Errorf("cannot use --rc-only with --system (system locations need no RC file)")
+			}
+			if opts.system && opts.outputDir != "" {
+				return fmt.Errorf("cannot use both --system and --output-dir")
+			}
+			if opts.homebrew {
+				if opts.system || opts.outputDir != "" {
+					return fmt.Errorf("cannot use --homebrew with --system or --output-dir")
+				}
+				if opts.paths.homebrewPrefix == "" {
+					return fmt.Errorf("--homebrew needs HOMEBREW_PREFIX; run 'eval \"$(brew shellenv)\"' first")
+				}
+			}
+			if opts.activeShell != "" {
+				name := opts.activeShell
+				if !slices.Contains(supportedShells, name) {
+					name = shellFromName(name)
+				}
+				if name == "" {
+					return fmt.Errorf("invalid --shell %q (valid: %s)", opts.activeShell, strings.Join(supportedShells, ", "))
+				}
+				opts.activeShell = name
+			}
+			if opts.nix {
+				if opts.system || opts.homebrew || opts.outputDir != "" || opts.rcFile != "" {
+					return fmt.Errorf("cannot use --nix with --system, --homebrew, --output-dir, or --rc-file")
+				}
+				if opts.writeRC || opts.rcOnly || opts.uninstallRC || opts.migrateRC {
+					return fmt.Errorf("--nix never edits RC files; drop --write-rc, --rc-only, --uninstall-rc, and --migrate-rc")
+				}
+				opts.xdgData, opts.completionsOnly = true, true
+			}
+			if list {
+				return listShells(cmd, opts)
+			}
+			if plan {
+				if opts.check || bundle != "" || save || cmd.Flags().Changed("restore") {
+					return fmt.Errorf("cannot use --plan with --check, --bundle, --save, or --restore")
+				}
+				opts.dryRun = true
+			}
+			if watch {
+				if opts.dryRun || opts.check || interactive || bundle != "" || fromBundle != "" || emitTo != "" ||
+					printBlock || pruneOrphans || opts.uninstall || opts.uninstallCompletions || opts.uninstallRC || cmd.Flags().Changed("restore") {
+					return fmt.Errorf("--watch only repeats an install; it cannot be combined with --dry-run, --check, --plan, --interactive, bundles, --emit-to, --print-rc-block, --prune-orphans, --restore, or uninstall flags")
+				}
+				return watchBinary(cmd, opts)
+			}
+			if combined != "" {
+				if opts.check || plan || bundle != "" || fromBundle != "" || emitTo != "" || opts.system || opts.outputDir != "" {
+					return fmt.Errorf("cannot use --combined with --check, --plan, bundles, --emit-to, --system, or --output-dir")
+				}
+				return writeCombined(cmd, combined, opts)
+			}
+			if opts.outputDir != "" {
+				if err := ensureWritableDir(opts.outputDir, opts.dryRun, opts.logger()); err != nil {
+					return err
+				}
+			}
+
+			// A plain install repeated with the same flags (e.g. from an RC
+			// hook) returns early when nothing it wrote or read has changed.
+			// --self-test is a check that has to run every time.
+			cacheable := !opts.dryRun && !opts.check && !opts.selfTest && !opts.forceDetect && summaryFile == "" && !opts.jsonOutput && !interactive && !save &&
+				bundle == "" && fromBundle == "" && emitTo == "" && !cmd.Flags().Changed("restore") && !pruneOrphans &&
+				!opts.uninstall && !opts.uninstallCompletions && !opts.uninstallRC && !opts.migrateRC
+			cachePath, cacheKey := shellCachePath(opts.paths), shellCacheKey(cmd.Flags(), opts.paths)
+			if cacheable && !refresh && shellCacheCurrent(cachePath, cacheKey) {
+				opts.logger().Debug("shell cache hit", "path", cachePath)
+				fmt.Fprintln(cmd.OutOrStdout(), "Shell completions are up to date (cached; use --refresh to regenerate).")
+				return shellExitStatus(cmd, nil, strict)
+			}
 
-			if bash {
-				status := shellStatus{shell: "bash"}
-				if err := writeShellCompletion(&status, root, "bash", force); err != nil {
-					fmt.Fprintf(cmd.ErrOrStderr(), "bash completion: %v\n", err)
+			cfg, err := loadShellConfig(opts.paths)
+			if err != nil {
+				return err
+			}
+			if cfg.WriteRC != nil && !cmd.Flags().Changed("write-rc") {
+				opts.writeRC = *cfg.WriteRC
+			}
+			if opts.rcOnly && !opts.uninstallRC && !opts.migrateRC {
+				opts.writeRC = true
+			}
+
+			changesRC := !opts.completionsOnly && !opts.system && (opts.writeRC || opts.uninstallRC || opts.migrateRC || opts.uninstall)
+			if changesRC && !yes {
+				if err := opts.setupConfirm(cmd, strictConfirm); err != nil {
+					return err
+				}
+			}
+
+			// Concurrent runs would interleave RC and manifest writes;
+			// anything that may write them holds the lock until it returns.
+			if !opts.dryRun && !opts.check && bundle == "" && emitTo == "" {
+				unlock, err := holdShellState(cmd, opts)
+				if err != nil {
+					return err
+				}
+				defer unlock()
+			}
+
+			if pruneOrphans {
+				return pruneOrphanCompletions(cmd, opts)
+			}
+
+			if fromBundle != "" {
+				if bundle != "" {
+					return fmt.Errorf("cannot use both --bundle and --install-bundle")
+				}
+				statuses, err := installBundle(cmd, fromBundle, opts)
+				if err != nil {
+					return err
+				}
+				if !opts.dryRun {
+					if err := updateShellManifest(statuses, opts.paths); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to update manifest: %v\n", err)
+					}
+				}
+				if plan {
+					return reportShellPlan(cmd, statuses, opts)
 				}
-				if writeRC && !uninstallRC {
-					if err := ensureShellRC(&status, "bash", force); err != nil {
-						fmt.Fprintf(cmd.ErrOrStderr(), "bash RC: %v\n", err)
+				if opts.jsonOutput {
+					if err := reportShellStatusJSON(cmd, statuses); err != nil {
+						return err
+					}
+				} else {
+					reportShellStatus(cmd, statuses, opts)
+				}
+				if summaryFile != "" {
+					if err := writeSummaryFile(cmd, summaryFile, statuses, opts); err != nil {
+						cmd.SilenceUsage = true
+						return err
 					}
 				}
-				if uninstallRC {
-					if err := removeRCBlock(bashRCPath()); err != nil {
-						fmt.Fprintf(cmd.ErrOrStderr(), "remove bash RC: %v\n", err)
-					} else {
-						status.rcRemoved = true
+				return shellExitStatus(cmd, statuses, strict)
+			}
+
+			selected := map[string]bool{
+				"bash":       bash,
+				"zsh":        zsh,
+				"fish":       fish,
+				"powershell": powershell,
+				"nushell":    nushell,
+				"elvish":     elvish,
+				"xonsh":      xonsh,
+				"tcsh":       tcsh,
+			}
+
+			picked := bash || zsh || fish || powershell || nushell || elvish || xonsh || tcsh
+			if !picked {
+				if interactive && isTerminal(cmd.OutOrStdout()) {
+					picked = true
+					current := opts.currentShell()
+					preselected := map[string]bool{current: true}
+					if len(cfg.Install) > 0 {
+						preselected = make(map[string]bool)
+						for _, sh := range cfg.Install {
+							preselected[sh] = true
+						}
+					}
+					if all {
+						for _, sh := range allShells(opts.paths.goos, os.Getenv("SHELL")) {
+							preselected[sh] = true
+						}
 					}
+					for _, sh := range promptShellSelection(cmd.InOrStdin(), cmd.OutOrStdout(), installedShells(current, opts.shellPaths), preselected) {
+						selected[sh] = true
+					}
+				} else if all {
+					for _, sh := range allShells(opts.paths.goos, os.Getenv("SHELL")) {
+						selected[sh] = true
+					}
+				} else if len(cfg.Install) > 0 {
+					for _, sh := range cfg.Install {
+						selected[sh] = true
+					}
+				} else if sh := opts.currentShell(); sh != "" {
+					selected[sh] = true
+				} else {
+					selected["bash"], selected["zsh"] = true, true
 				}
-				statuses = append(statuses, status)
 			}
 
-			if zsh {
-				status := shellStatus{shell: "zsh"}
-				if err := writeShellCompletion(&status, root, "zsh", force); err != nil {
-					fmt.Fprintf(cmd.ErrOrStderr(), "zsh completion: %v\n", err)
+			opts.scopedUninstall = picked && !all
+
+			var shells []string
+			for _, sh := range supportedShells {
+				if selected[sh] {
+					shells = append(shells, sh)
 				}
-				if writeRC && !uninstallRC {
-					if err := ensureShellRC(&status, "zsh", force); err != nil {
-						fmt.Fprintf(cmd.ErrOrStderr(), "zsh RC: %v\n", err)
+			}
+			if len(shells) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No shells selected.")
+				return nil
+			}
+			if opts.nix {
+				for _, sh := range shells {
+					if !slices.Contains(nixShells, sh) {
+						return fmt.Errorf("--nix supports %s; select those shells instead of %s", strings.Join(nixShells, ", "), sh)
 					}
 				}
-				if uninstallRC {
-					if err := removeRCBlock(zshRCPath()); err != nil {
-						fmt.Fprintf(cmd.ErrOrStderr(), "remove zsh RC: %v\n", err)
-					} else {
-						status.rcRemoved = true
+			}
+			if opts.rcFile != "" && cmd.Flags().Changed("rc-target") {
+				return fmt.Errorf("cannot use both --rc-file and --rc-target")
+			}
+			if opts.rcFile != "" && len(shells) != 1 {
+				return fmt.Errorf("--rc-file applies to a single shell, but %d are selected (%s)", len(shells), strings.Join(shells, ", "))
+			}
+			if printBlock {
+				if opts.nix || opts.system {
+					return fmt.Errorf("--print-rc-block has nothing to print with --nix or --system, which need no RC block")
+				}
+				return printRCBlocks(cmd, shells, opts)
+			}
+
+			if save {
+				if opts.dryRun {
+					fmt.Fprintf(cmd.OutOrStdout(), "Would save shell.install: %s (dry-run)\n", strings.Join(shells, ", "))
+				} else {
+					path, err := saveShellConfig(opts.paths, shells)
+					if err != nil {
+						return fmt.Errorf("failed to save shell selection: %w", err)
 					}
+					fmt.Fprintf(cmd.OutOrStdout(), "Saved shell.install: %s to %s\n", strings.Join(shells, ", "), path)
 				}
-				statuses = append(statuses, status)
 			}
 
-			if fish {
-				status := shellStatus{shell: "fish"}
-				if err := writeShellCompletion(&status, root, "fish", force); err != nil {
-					fmt.Fprintf(cmd.ErrOrStderr(), "fish completion: %v\n", err)
+			if bundle != "" {
+				if emitTo != "" {
+					return fmt.Errorf("cannot use both --bundle and --emit-to")
 				}
-				statuses = append(statuses, status)
+				return writeBundle(cmd, bundle, shells, opts)
+			}
+			if emitTo != "" {
+				return emitShells(cmd, emitTo, shells, opts)
 			}
 
-			if powershell {
-				status := shellStatus{shell: "powershell"}
-				if err := writeShellCompletion(&status, root, "powershell", force); err != nil {
-					fmt.Fprintf(cmd.ErrOrStderr(), "powershell completion: %v\n", err)
+			if opts.check {
+				cmd.SilenceUsage = true
+				return checkShells(cmd, cmd.Root(), shells, opts)
+			}
+
+			if cmd.Flags().Changed("restore") {
+				return restoreShellRC(cmd, shells, opts)
+			}
+
+			var statuses []shellStatus
+			var installErr error
+			root := cmd.Root()
+
+			if opts.uninstall {
+				statuses = uninstallShells(cmd, shells, opts)
+			} else if opts.uninstallCompletions {
+				statuses = uninstallCompletions(cmd, shells, opts)
+				if !opts.dryRun {
+					if err := updateShellManifest(statuses, opts.paths); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to update manifest: %v\n", err)
+					}
+				}
+			} else {
+				statuses, installErr = installShellStatuses(InstallOptions{Root: root, Shells: shells, Stderr: cmd.ErrOrStderr(), shell: &opts})
+				if cacheable {
+					if err := saveShellCache(cachePath, cacheKey, statuses, shellCacheInputs(opts.paths, templateFile)); err != nil {
+						opts.logger().Warn("failed to update shell cache", "error", err)
+					}
 				}
-				statuses = append(statuses, status)
 			}
 
-			reportShellStatus(cmd, statuses, uninstallRC)
-			return nil
+			if plan {
+				if err := reportShellPlan(cmd, statuses, opts); err != nil {
+					return err
+				}
+			} else if opts.jsonOutput {
+				if err := reportShellStatusJSON(cmd, statuses); err != nil {
+					return err
+				}
+			} else {
+				reportShellStatus(cmd, statuses, opts)
+			}
+			if summaryFile != "" && !plan {
+				if err := writeSummaryFile(cmd, summaryFile, statuses, opts); err != nil {
+					cmd.SilenceUsage = true
+					return err
+				}
+			}
+			if err := shellExitStatus(cmd, statuses, strict); err != nil {
+				return err
+			}
+			return installErr
 		},
 	}
 
@@ -134,78 +724,408 @@ unless --force is used. RC file blocks are added once and not duplicated.`,
 	cmd.Flags().BoolVar(&zsh, "zsh", false, "Install zsh completion")
 	cmd.Flags().BoolVar(&fish, "fish", false, "Install fish completion")
 	cmd.Flags().BoolVar(&powershell, "powershell", false, "Install PowerShell completion")
-	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
-	cmd.Flags().BoolVar(&writeRC, "write-rc", false, "Append idempotent RC lines to enable completions")
-	cmd.Flags().BoolVar(&uninstallRC, "uninstall-rc", false, "Remove RC lines previously added by arc")
-	cmd.Flags().BoolVar(&all, "all", false, "Install completions for all supported shells")
+	cmd.Flags().BoolVar(&nushell, "nushell", false, "Install nushell completion")
+	cmd.Flags().BoolVar(&elvish, "elvish", false, "Install elvish completion")
+	cmd.Flags().BoolVar(&xonsh, "xonsh", false, "Install xonsh completion")
+	cmd.Flags().BoolVar(&tcsh, "tcsh", false, "Install tcsh completion")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Overwrite existing completion files")
+	cmd.Flags().BoolVar(&opts.forceRC, "force-rc", false, "Rewrite an outdated RC block in place without overwriting completion files")
+	cmd.Flags().StringVar(&opts.rcTargetMode, "rc-target", "auto", "Which bash/zsh startup file gets the RC block: interactive, login, or auto (~/.bashrc if it exists, and .zshrc)")
+	cmd.Flags().StringVar(&opts.rcFile, "rc-file", "", "Use this RC file instead of the shell's default (requires exactly one shell)")
+	cmd.Flags().BoolVar(&opts.completionsOnly, "completions-only", false, "Only write completion files; skip all RC handling even with --write-rc")
+	cmd.Flags().BoolVar(&opts.rcOnly, "rc-only", false, "Only manage RC blocks; leave completion files untouched (implies --write-rc)")
+	cmd.Flags().BoolVar(&opts.writeRC, "write-rc", false, "Append idempotent RC lines to enable completions")
+	cmd.Flags().BoolVar(&opts.uninstallRC, "uninstall-rc", false, "Remove RC lines previously added by arc")
+	cmd.Flags().BoolVar(&opts.migrateRC, "migrate-rc", false, "Replace RC blocks that use legacy markers with the current block")
+	cmd.Flags().BoolVar(&opts.uninstallCompletions, "uninstall-completions", false, "Remove completion files arc installed for the selected shells")
+	cmd.Flags().BoolVar(&opts.uninstall, "uninstall", false, "Remove completion files and RC blocks recorded in the install manifest")
+	cmd.Flags().StringVar(&opts.restore, "restore", "", "Restore the latest RC backup, or the one matching the given timestamp, to the RC file the other flags select")
+	cmd.Flags().Lookup("restore").NoOptDefVal = "latest"
+	cmd.Flags().BoolVar(&opts.followSymlinks, "follow-symlinks", false, "Edit the target of a symlinked RC file instead of refusing")
+	cmd.Flags().BoolVar(&opts.cleanBackups, "clean-backups", false, "With --uninstall-rc or --uninstall, also delete the RC backups arc-init made")
+	cmd.Flags().IntVar(&opts.keepBackups, "keep-backups", defaultKeepBackups, "Number of timestamped RC and completion backups to keep per file (0 disables backups)")
+	cmd.Flags().BoolVar(&list, "list-shells", false, "List supported shells, whether each is installed and has completions, and which is active")
+	cmd.Flags().BoolVar(&all, "all", false, "Install completions for the usual shells of this OS (tcsh only with --tcsh)")
+	cmd.Flags().BoolVar(&save, "save", false, "Save the selected shells as shell.install in the global config")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Choose shells from a checklist when no shell flag is given (TTY only)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show what would be written without touching the filesystem")
+	cmd.Flags().BoolVar(&pruneOrphans, "prune-orphans", false, "Remove arc-init completion files left in managed locations the current flags no longer install to")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Reinstall completions each time the arc binary is rebuilt, until interrupted")
+	cmd.Flags().BoolVar(&printBlock, "print-rc-block", false, "Print the RC block --write-rc would add for each selected shell, writing nothing")
+	cmd.Flags().StringVar(&summaryFile, "summary-file", "", "Also write the status report (JSON with --json), with a timestamp and the arc-init version, to this file")
+	cmd.Flags().BoolVar(&plan, "plan", false, "Print the operations this run would perform as a JSON plan, changing nothing")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", defaultCommandTimeout, "Give up on external shell commands (syntax checks, detection) after this long")
+	cmd.Flags().BoolVar(&opts.check, "check", false, "Report stale or missing completion files without writing; exits non-zero on drift")
+	cmd.Flags().BoolVar(&opts.jsonOutput, "json", false, "Print the status report as JSON")
+	cmd.Flags().BoolVar(&opts.onlyChanges, "report-only-changes", false, "Report only shells where something was written or removed, or one line when nothing changed")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable colored output (also honors NO_COLOR)")
+	cmd.Flags().StringVar(&profile, "profile", "", "Use the path conventions of another OS: linux, macos, or windows (default: host OS)")
+	cmd.Flags().BoolVar(&opts.system, "system", false, "Install completions system-wide for all users (bash, zsh, fish; skips RC files)")
+	cmd.Flags().StringVar(&bundle, "bundle", "", "Write the selected completions, an install script, and a target manifest to this tar.gz instead of installing")
+	cmd.Flags().StringVar(&combined, "combined", "", "Write the active shell's completions to this single sourceable file, leaving RC files alone")
+	cmd.Flags().StringVar(&emitTo, "emit-to", "", "Stage completion files and RC blocks in this directory for a dotfile manager instead of installing")
+	cmd.Flags().StringVar(&fromBundle, "install-bundle", "", "Install the completions from a --bundle archive into this host's completion paths (honors --force)")
+	cmd.Flags().BoolVar(&opts.selfTest, "self-test", false, "After installing, load each completion in a fresh shell and check that it offers candidates")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Change RC files without asking (prompts appear only on a terminal)")
+	cmd.Flags().BoolVar(&strictConfirm, "strict-confirm", false, "Refuse to change RC files without --yes when no terminal is available to ask")
+	cmd.Flags().BoolVar(&opts.includeHidden, "include-hidden-commands", false, "With --dynamic=false, also complete commands marked hidden (exposes internal commands)")
+	cmd.Flags().BoolVar(&dynamic, "dynamic", true, "Have the bash script ask arc-init for candidates at completion time; --dynamic=false bakes in the command tree")
+	cmd.Flags().BoolVar(&opts.xdgData, "xdg-data", false, "Install bash, zsh, and fish completions under $XDG_DATA_HOME (~/.local/share) instead of ~/.config")
+	cmd.Flags().BoolVar(&opts.skipMissing, "skip-missing", false, "Skip shells whose binary is not on PATH instead of installing orphaned completions")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit with status 3 when nothing was written or removed")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Ignore the install cache and regenerate every completion")
+	cmd.Flags().StringVar(&templateFile, "template-file", "", "Render each completion file through this Go text/template (receives .Body, .Shell, .Command, .Version, .Path)")
+	cmd.Flags().StringVar(&opts.commandName, "command-name", "", "Command the completions bind to (default \"arc\")")
+	cmd.Flags().StringArrayVar(&shellPaths, "shell-path", nil, "Use this executable for a shell, as NAME=PATH, for syntax checks, self-tests, and detection (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.aliases, "alias", nil, "Also complete this alias of arc-init (repeatable)")
+	cmd.Flags().BoolVar(&opts.noDescriptions, "no-descriptions", false, "Generate bash, zsh, fish, and PowerShell completions without candidate descriptions")
+	cmd.Flags().StringVar(&opts.activeShell, "shell", "", "Treat this as the current shell instead of detecting it (picks the default install)")
+	cmd.Flags().BoolVar(&opts.forceDetect, "force-detect", false, "Detect the current shell from the parent process before $SHELL, bypassing the install cache")
+	cmd.Flags().BoolVar(&opts.nix, "nix", false, "Install bash, zsh, and fish completions for home-manager without editing RC files, and print the home.nix settings to add")
+	cmd.Flags().BoolVar(&opts.homebrew, "homebrew", false, "Install bash, zsh, and fish completions under $HOMEBREW_PREFIX")
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", "", "Write completion files to this directory instead of the per-shell default (~ and $VAR are expanded)")
 
 	return cmd
 }
 
-func writeShellCompletion(status *shellStatus, root *cobra.Command, shell string, force bool) error {
+// installShells runs installShell for each shell concurrently. Each goroutine
+// fills its own slot, so statuses keep the order of shells. RC edits share one
+// transaction: if any shell's RC step fails, every RC file changed in the run
+// is restored.
+func installShells(stderr io.Writer, root *cobra.Command, shells []string, opts shellOptions) []shellStatus {
+	if !opts.dryRun {
+		opts.rcTxn = &rcTxn{}
+	}
+	statuses := make([]shellStatus, len(shells))
+	var wg sync.WaitGroup
+	for i, sh := range shells {
+		wg.Add(1)
+		go func(i int, sh string) {
+			defer wg.Done()
+			statuses[i] = installShellIsolated(stderr, root, sh, opts)
+		}(i, sh)
+	}
+	wg.Wait()
+
+	restored, err := opts.rcTxn.rollback(opts.logger())
+	if err != nil {
+		fmt.Fprintf(stderr, "warning: RC rollback incomplete: %v\n", err)
+	}
+	if len(restored) > 0 {
+		fmt.Fprintf(stderr, "RC changes rolled back: %s\n", strings.Join(restored, ", "))
+		for i := range statuses {
+			s := &statuses[i]
+			if slices.Contains(restored, s.rcPath) || slices.Contains(restored, s.profilePath) {
+				s.rcWritten, s.rcMigrated, s.rcRemoved = false, false, false
+				s.profilePath, s.profileRemoved = "", false
+				s.rcRolledBack = true
+			}
+		}
+	}
+	return statuses
+}
+
+// installShell writes the completion file for shell and applies any requested
+// RC changes. Errors are reported on stderr so the remaining shells still run.
+func installShell(stderr io.Writer, root *cobra.Command, shell string, opts shellOptions) shellStatus {
+	status := shellStatus{shell: shell, dryRun: opts.dryRun, rcPath: opts.rcPathFor(shell), rcKind: opts.rcTargetKind(shell)}
+
+	if opts.skipMissing && shellBinary(shell, opts.shellPaths) == "" {
+		opts.logger().Debug("shell not on PATH", "shell", shell, "binaries", shellBinaries[shell])
+		status.skipped, status.shellMissing = true, true
+		status.reason = fmt.Sprintf("not installed: no %s on PATH", strings.Join(shellBinaries[shell], " or "))
+		status.rcPath, status.rcKind = "", ""
+		return status
+	}
+
+	if !opts.rcOnly {
+		if err := writeShellCompletion(&status, root, shell, opts); err != nil && !errors.Is(err, ErrCompletionExists) {
+			status.addError(stderr, fmt.Errorf("%s completion: %w", shell, err))
+		} else if opts.selfTest && !opts.dryRun && status.path != "" {
+			if err := runSelfTest(&status, shell, opts); err != nil {
+				status.addError(stderr, fmt.Errorf("%s completion: %w", shell, err))
+			}
+		}
+	}
+	if opts.completionsOnly {
+		return status
+	}
+	if opts.system {
+		status.rcPath = ""
+		if opts.writeRC || opts.uninstallRC || opts.migrateRC {
+			status.rcSkipped = true
+			status.rcReason = "system locations are loaded automatically"
+		}
+		return status
+	}
+	if opts.migrateRC && !opts.uninstallRC {
+		if err := migrateShellRC(&status, shell, opts); err != nil {
+			opts.rcTxn.fail()
+			status.addError(stderr, fmt.Errorf("migrate %s RC: %w", shell, err))
+		}
+	}
+	if opts.writeRC && !opts.uninstallRC && !status.rcMigrated {
+		if err := ensureShellRC(&status, shell, opts); err != nil && !errors.Is(err, ErrRCBlockPresent) {
+			opts.rcTxn.fail()
+			status.addError(stderr, fmt.Errorf("%s RC: %w", shell, err))
+		} else if shell == "bash" && opts.paths.goos == "darwin" {
+			if err := ensureBashProfileSourcesBashrc(&status, opts); err != nil {
+				opts.rcTxn.fail()
+				status.addError(stderr, fmt.Errorf("bash login profile: %w", err))
+			}
+		}
+	}
+	if opts.uninstallRC && status.rcPath != "" {
+		var removed bool
+		rc, err := opts.rcTarget(status.rcPath)
+		if err == nil {
+			status.rcPath = rc
+			if diff := rcRemovalDiff(rc); diff != "" && !opts.dryRun && !opts.confirm.confirm("remove the arc block from", rc, diff) {
+				status.rcSkipped = true
+				status.rcReason = rcDeclinedReason
+				return status
+			}
+			removed, err = opts.rcTxn.remove(rc, opts.dryRun, opts.keepBackups, opts.logger())
+		}
+		if err != nil {
+			opts.rcTxn.fail()
+			status.addError(stderr, fmt.Errorf("remove %s RC: %w", shell, err))
+		} else if removed {
+			status.rcRemoved = true
+			if opts.dryRun {
+				status.rcDiff = rcRemovalDiff(status.rcPath)
+			}
+			if opts.cleanBackups {
+				status.cleanRCBackups(stderr, opts)
+			}
+		}
+		if shell == "bash" && isBashProfile(opts.paths.loginRCPathFor("bash"), opts) {
+			if err := removeBashProfileBlock(&status, opts.paths.loginRCPathFor("bash"), opts); err != nil {
+				opts.rcTxn.fail()
+				status.addError(stderr, fmt.Errorf("bash login profile: %w", err))
+			}
+		}
+	}
+
+	return status
+}
+
+// installShellIsolated is installShell with a panic turned into an error
+// status for that shell. Shells install on their own goroutines, where a
+// panic (say, from a write helper on an unusual filesystem) would otherwise
+// kill the whole run, including shells that were fine.
+func installShellIsolated(stderr io.Writer, root *cobra.Command, shell string, opts shellOptions) (status shellStatus) {
+	defer func() {
+		if r := recover(); r != nil {
+			opts.logger().Debug("recovered panic", "shell", shell, "panic", r, "stack", string(debug.Stack()))
+			status = shellStatus{shell: shell, dryRun: opts.dryRun}
+			status.reason = fmt.Sprint(r)
+			status.addError(stderr, fmt.Errorf("%s: %w: %v (run with -v for the stack trace)", shell, ErrShellPanic, r))
+		}
+	}()
+	return installShell(stderr, root, shell, opts)
+}
+
+// stderrMu serializes error output from concurrent installs.
+var stderrMu sync.Mutex
+
+// addError records err on the status and echoes it to stderr.
+func (s *shellStatus) addError(stderr io.Writer, err error) {
+	s.failures = append(s.failures, err)
+	s.errs = append(s.errs, err.Error())
+	s.errCodes = append(s.errCodes, errorCode(err))
+	stderrMu.Lock()
+	defer stderrMu.Unlock()
+	fmt.Fprintln(stderr, err)
+}
+
+func writeShellCompletion(status *shellStatus, root *cobra.Command, shell string, opts shellOptions) error {
 	var (
 		path string
 		err  error
 	)
 
-	switch shell {
-	case "bash":
-		path, err = writeBashCompletion(root, force)
-	case "zsh":
-		path, err = writeZshCompletion(root, force)
-	case "fish":
-		path, err = writeFishCompletion(root, force)
-	case "powershell":
-		path, err = writePSCompletion(root, force)
-	default:
-		return fmt.Errorf("unknown shell: %s", shell)
+	status.path, err = completionPath(shell, opts)
+	if err != nil {
+		return err
+	}
+	status.conflicts = findConflictingCompletions(shell, status.path)
+
+	if !opts.force {
+		_, err := os.Stat(status.path)
+		opts.logger().Debug("stat completion file", "path", status.path, "exists", err == nil)
+		if err == nil {
+			status.skipped = true
+			status.reason = "completion file already exists (use --force to overwrite)"
+			return ErrCompletionExists
+		}
 	}
 
+	if opts.system {
+		if err := ensureWritableDir(filepath.Dir(status.path), opts.dryRun, opts.logger()); err != nil {
+			status.reason = "permission denied"
+			if errors.Is(err, fs.ErrPermission) {
+				return fmt.Errorf("--system needs write access to %s (try again with sudo): %w", filepath.Dir(status.path), err)
+			}
+			return err
+		}
+	}
+
+	script, err := completionScript(root, shell, status.path, opts)
 	if err != nil {
 		return err
 	}
+	if supportsDescriptions(shell) && (shell != "bash" || !opts.staticBash) {
+		status.descriptions = "on"
+		if opts.noDescriptions {
+			status.descriptions = "off"
+		}
+	}
+	if shell != "nushell" {
+		status.aliases = opts.aliases
+	}
+	var aliasFiles map[string][]byte
+	if shell == "fish" {
+		aliasFiles = fishAliasFiles(filepath.Dir(status.path), opts.command(), opts.aliases)
+		for p := range aliasFiles {
+			status.aliasPaths = append(status.aliasPaths, p)
+		}
+		sort.Strings(status.aliasPaths)
+	}
 
-	if path == "" {
-		status.skipped = true
-		status.reason = "completion file already exists (use --force to overwrite)"
-	} else {
+	checked, err := validateCompletion(shell, script, opts)
+	if errors.Is(err, errCheckTimeout) {
+		opts.logger().Warn("skipping syntax check", "shell", shell, "timeout", opts.commandTimeout())
+		status.validation = fmt.Sprintf("skipped (%s syntax checker timed out after %s)", shell, opts.commandTimeout())
+	} else if err != nil {
+		status.reason = fmt.Sprintf("syntax check failed: %v", err)
+		return fmt.Errorf("%w: %w", ErrSyntaxCheck, err)
+	}
+	if checked {
+		status.validation = "passed"
+	} else if status.validation == "" {
+		status.validation = "skipped (no " + shell + " syntax checker available)"
+	}
+	status.commandWarning = checkCommandBinding(shell, script, opts.command())
+
+	// Identical files are not rewritten, even with --force, so their mtimes
+	// (and any dotfile repo tracking them) stay untouched.
+	mainUnchanged := completionUnchanged(status.path, script)
+	var pending []string
+	for _, p := range status.aliasPaths {
+		if !completionUnchanged(p, aliasFiles[p]) {
+			pending = append(pending, p)
+		}
+	}
+	opts.logger().Debug("compare completion file", "path", status.path, "unchanged", mainUnchanged, "aliases_pending", len(pending))
+	if mainUnchanged && len(pending) == 0 {
+		status.unchanged = true
+		return nil
+	}
+
+	if opts.dryRun {
 		status.written = true
+		return nil
+	}
+
+	toBackUp := pending
+	if !mainUnchanged {
+		toBackUp = append([]string{status.path}, pending...)
+	}
+	for _, p := range toBackUp {
+		backup, err := backupCompletion(p, opts.keepBackups, opts.logger())
+		if err != nil {
+			return fmt.Errorf("failed to back up %s: %w", p, err)
+		}
+		if backup != "" {
+			opts.logger().Debug("backed up completion file", "path", p, "backup", backup)
+			status.backups = append(status.backups, backup)
+		}
+	}
+
+	path = status.path
+	if !mainUnchanged {
+		path, err = writeCompletion(shell, script, opts)
+		if err != nil {
+			return err
+		}
+	}
+	for _, p := range pending {
+		if err := writeCompletionFile(p, aliasFiles[p], opts.logger()); err != nil {
+			return err
+		}
 	}
 
+	status.path = path
+	status.written = true
 	return nil
 }
 
-func ensureShellRC(status *shellStatus, shell string, force bool) error {
-	var path string
-	var block string
-
-	if shell == "bash" {
-		path = bashRCPath()
-		block = rcStart + "\n" + `# Arc bash completions
-if [ -f "$HOME/.config/bash/completions/arc.bash" ]; then
-  . "$HOME/.config/bash/completions/arc.bash"
-fi` + "\n" + rcEnd + "\n"
-	} else if shell == "zsh" {
-		path = zshRCPath()
-		block = rcStart + "\n" + `# Arc zsh completions
-fpath+=(~/.zsh/completions)
-autoload -Uz compinit
-compinit` + "\n" + rcEnd + "\n"
+func ensureShellRC(status *shellStatus, shell string, opts shellOptions) error {
+	if shell == "zsh" {
+		status.rcStrategy = opts.paths.zshFramework()
+	}
+	if !needsRCBlock(shell, opts) {
+		status.rcSkipped = true
+		status.rcReason = noRCBlockReason(shell, opts)
+		return nil
 	}
 
-	dir := filepath.Dir(path)
-	_ = os.MkdirAll(dir, 0o755)
+	path, block, err := rcBlockFor(shell, opts)
+	if err != nil {
+		return err
+	}
+	if path, err = opts.rcTarget(path); err != nil {
+		return err
+	}
+	status.rcPath = path
 
-	if data, err := os.ReadFile(path); err == nil {
-		content := string(data)
-		if strings.Contains(content, rcStart) && strings.Contains(content, rcEnd) {
-			status.rcSkipped = true
-			status.reason = "RC block already present (use --force to update)"
-			return nil
+	data, err := os.ReadFile(path)
+	opts.logger().Debug("read RC file", "path", path, "err", err)
+	content := string(data)
+	updated := blockedit.Append(content, block)
+	if err == nil {
+		if _, _, ok := findRCBlock(content, currentRCMarkers); ok {
+			var changed bool
+			updated, changed = blockedit.Splice(content, rcStart, rcEnd, block)
+			if !changed {
+				status.rcSkipped, status.rcUnchanged = true, true
+				status.rcReason = rcUpToDateReason
+				return ErrRCBlockPresent
+			}
+			if !opts.force && !opts.forceRC {
+				status.rcSkipped = true
+				status.rcReason = "RC block is outdated (use --force or --force-rc to update)"
+				return ErrRCBlockPresent
+			}
+			status.rcUpdated = true
+		} else if _, _, ok := findLegacyRCBlock(content); ok {
+			return migrateShellRC(status, shell, opts)
 		}
 	}
 
-	if err := upsertRCBlock(path, block, force); err != nil {
+	if opts.dryRun {
+		status.rcWritten = true
+		status.rcBlock = block
+		status.rcDiff = blockedit.Diff(path, content, updated)
+		return nil
+	}
+
+	action := "add the arc block to"
+	if status.rcUpdated {
+		action = "update the arc block in"
+	}
+	if !opts.confirm.confirm(action, path, blockedit.Diff(path, content, updated)) {
+		status.rcSkipped, status.rcUpdated = true, false
+		status.rcReason = rcDeclinedReason
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0o755)
+	opts.logger().Debug("mkdir", "path", dir, "err", err)
+
+	if err := opts.rcTxn.upsert(path, block, status.rcUpdated, opts.keepBackups, opts.logger()); err != nil {
 		return err
 	}
 
@@ -213,150 +1133,854 @@ compinit` + "\n" + rcEnd + "\n"
 	return nil
 }
 
-func reportShellStatus(cmd *cobra.Command, statuses []shellStatus, uninstalled bool) {
+// noRCBlockReason explains why needsRCBlock is false for shell.
+func noRCBlockReason(shell string, opts shellOptions) string {
+	switch shell {
+	case "zsh":
+		return "oh-my-zsh loads completions from " + completionDir("zsh", opts)
+	case "fish":
+		return "fish auto-loads completions from " + completionDir("fish", shellOptions{paths: opts.paths, homebrew: opts.homebrew})
+	}
+	return "no RC integration for " + shell
+}
+
+// printRCBlocks writes the RC block ensureShellRC would add for each shell to
+// stdout, preceded by the file it belongs in when there is more than one.
+// Shells that need no block are noted on stderr.
+func printRCBlocks(cmd *cobra.Command, shells []string, opts shellOptions) error {
+	out := cmd.OutOrStdout()
+	printed := 0
+	for _, sh := range shells {
+		if !needsRCBlock(sh, opts) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: no RC block needed (%s)\n", sh, noRCBlockReason(sh, opts))
+			continue
+		}
+		path, block, err := rcBlockFor(sh, opts)
+		if err != nil {
+			return fmt.Errorf("%s RC block: %w", sh, err)
+		}
+		if len(shells) > 1 {
+			if printed > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintf(out, "# %s: add to %s\n", sh, opts.paths.homeRelative(path))
+		}
+		fmt.Fprint(out, block)
+		printed++
+	}
+	return nil
+}
+
+// rcBlockFor returns the RC file for shell and the marker-delimited block
+// that loads its completions. The sourced path comes from completionDir, so
+// the block always points where the completion file was written.
+func rcBlockFor(shell string, opts shellOptions) (string, string, error) {
+	switch shell {
+	case "bash":
+		source := opts.paths.homeRelative(filepath.Join(completionDir("bash", opts), completionFileName("bash", opts)))
+		if opts.paths.msys() {
+			source = msysPath(source)
+		}
+		return opts.rcPathFor("bash"), rcStart + "\n" + `# Arc bash completions
+if [ -f "` + source + `" ]; then
+  . "` + source + `"
+fi` + "\n" + rcEnd + "\n", nil
+	case "zsh":
+		dir := opts.paths.homeRelative(completionDir("zsh", opts))
+		if fw := opts.paths.zshFramework(); fw != zshVanilla {
+			// The framework already ran compinit; register the function
+			// directly instead of initializing completion a second time.
+			fn := completionFileName("zsh", opts)
+			return opts.rcPathFor("zsh"), rcStart + "\n" + `# Arc zsh completions (` + fw + ` runs compinit)
+fpath+=("` + dir + `")
+autoload -Uz ` + fn + ` && compdef ` + fn + ` ` + opts.command() + "\n" + rcEnd + "\n", nil
+		}
+		return opts.rcPathFor("zsh"), rcStart + "\n" + `# Arc zsh completions
+fpath+=("` + dir + `")
+autoload -Uz compinit
+compinit` + "\n" + rcEnd + "\n", nil
+	case "fish":
+		dir := opts.paths.homeRelative(completionDir("fish", opts))
+		return opts.rcPathFor("fish"), rcStart + "\n" + `# Arc fish completions
+if not contains -- "` + dir + `" $fish_complete_path
+    set -g fish_complete_path "` + dir + `" $fish_complete_path
+end` + "\n" + rcEnd + "\n", nil
+	case "powershell":
+		source := opts.paths.homeRelative(filepath.Join(completionDir("powershell", opts), completionFileName("powershell", opts)))
+		return opts.rcPathFor("powershell"), rcStart + "\n" + `# Arc PowerShell completions
+if (Test-Path "` + source + `") {
+    . "` + source + `"
+}` + "\n" + rcEnd + "\n", nil
+	case "xonsh":
+		source := opts.paths.homeRelative(filepath.Join(completionDir("xonsh", opts), completionFileName("xonsh", opts)))
+		return opts.rcPathFor("xonsh"), rcStart + "\n" + `# Arc xonsh completions
+import os.path as _arc_path
+if _arc_path.isfile(_arc_path.expandvars("` + source + `")):
+    source @(_arc_path.expandvars("` + source + `"))
+del _arc_path` + "\n" + rcEnd + "\n", nil
+	case "tcsh":
+		source := opts.paths.homeRelative(filepath.Join(completionDir("tcsh", opts), completionFileName("tcsh", opts)))
+		return opts.rcPathFor("tcsh"), rcStart + "\n" + `# Arc tcsh completions
+if ( -f "` + source + `" ) source "` + source + `"` + "\n" + rcEnd + "\n", nil
+	}
+	return "", "", fmt.Errorf("no RC integration for %s", shell)
+}
+
+// shellChanged reports whether anything was written, removed, or failed for
+// s, which --report-only-changes keeps in the report.
+func shellChanged(s shellStatus) bool {
+	return s.written || s.completionRemoved || s.rcWritten || s.rcRemoved || s.rcMigrated ||
+		s.rcRolledBack || s.profilePath != "" || len(s.backupsRemoved) > 0 || len(s.errs) > 0
+}
+
+func reportShellStatus(cmd *cobra.Command, statuses []shellStatus, opts shellOptions) {
 	if len(statuses) == 0 {
 		return
 	}
+	all := statuses
+	if opts.onlyChanges {
+		var changed []shellStatus
+		for _, s := range statuses {
+			if shellChanged(s) {
+				changed = append(changed, s)
+			}
+		}
+		if len(changed) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "All selected shells are up to date; nothing changed.")
+			return
+		}
+		statuses = changed
+	}
+
+	uninstalled := opts.uninstallRC || opts.uninstallCompletions || opts.uninstall
+	c := newColorizer(cmd.OutOrStdout(), opts.noColor)
 
 	fmt.Fprintln(cmd.OutOrStdout())
 	fmt.Fprintln(cmd.OutOrStdout(), "=== Shell Completions Status ===")
 	fmt.Fprintln(cmd.OutOrStdout())
+	if opts.homebrew {
+		fmt.Fprintf(cmd.OutOrStdout(), "Homebrew prefix: %s\n", opts.paths.homebrewPrefix)
+		fmt.Fprintln(cmd.OutOrStdout())
+	}
+	if opts.paths.msys() {
+		fmt.Fprintf(cmd.OutOrStdout(), "Environment: Git Bash / MSYS2 (MSYSTEM=%s)\n", opts.paths.msystem)
+		fmt.Fprintln(cmd.OutOrStdout())
+	}
 
+	dryRun := false
 	for _, s := range statuses {
 		fmt.Fprintf(cmd.OutOrStdout(), "%s:\n", strings.ToUpper(s.shell))
 
+		if s.dryRun {
+			dryRun = true
+			reportShellDryRun(cmd, s, uninstalled, c)
+			fmt.Fprintln(cmd.OutOrStdout())
+			continue
+		}
+
 		if uninstalled {
-			fmt.Fprintln(cmd.OutOrStdout(), "  RC block: REMOVED")
+			if s.completionRemoved {
+				fmt.Fprintln(cmd.OutOrStdout(), "  Completions: "+c.green("REMOVED"))
+			} else if opts.uninstallCompletions && s.skipped {
+				fmt.Fprintf(cmd.OutOrStdout(), "  Completions: %s (%s)\n", c.yellow("SKIPPED"), s.reason)
+			}
+			if s.rcRemoved {
+				fmt.Fprintln(cmd.OutOrStdout(), "  RC block: "+c.green("REMOVED"))
+			}
+			if s.profileRemoved {
+				fmt.Fprintf(cmd.OutOrStdout(), "  Login shell: %s (block removed from %s)\n", c.green("REMOVED"), s.profilePath)
+			}
+			for _, b := range s.backupsRemoved {
+				fmt.Fprintf(cmd.OutOrStdout(), "  RC backup: %s %s\n", c.green("REMOVED"), b)
+			}
 		} else if s.written {
-			fmt.Fprintln(cmd.OutOrStdout(), "  Completions: INSTALLED")
+			fmt.Fprintln(cmd.OutOrStdout(), "  Completions: "+c.green("INSTALLED"))
+		} else if s.unchanged {
+			fmt.Fprintln(cmd.OutOrStdout(), "  Completions: "+c.green("UNCHANGED")+" (content identical, not rewritten)")
+		} else if s.shellMissing {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Completions: %s (%s)\n", c.yellow("SKIPPED"), s.reason)
 		} else if s.skipped {
-			fmt.Fprintf(cmd.OutOrStdout(), "  Completions: SKIPPED (already exists, %s)\n", s.reason)
+			fmt.Fprintf(cmd.OutOrStdout(), "  Completions: %s (already exists, %s)\n", c.yellow("SKIPPED"), s.reason)
+		} else if s.reason != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Completions: %s (%s)\n", c.red("FAILED"), s.reason)
+		}
+
+		if s.validation != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Syntax check: %s\n", s.validation)
+		}
+		if s.selfTest != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Self-test: %s\n", s.selfTest)
+		}
+		if s.commandWarning != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Command: %s (%s)\n", c.yellow("WARNING"), s.commandWarning)
+		}
+		if s.written && len(s.aliases) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Aliases: %s\n", strings.Join(s.aliases, ", "))
+		}
+		if (s.written || s.unchanged) && s.descriptions != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Descriptions: %s\n", s.descriptions)
+		}
+		for _, b := range s.backups {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Backup: %s\n", b)
+		}
+
+		if len(s.conflicts) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Conflicts: %s\n", c.yellow("WARNING"))
+			for _, p := range s.conflicts {
+				fmt.Fprintf(cmd.OutOrStdout(), "    - %s\n", p)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "    Another arc completion is installed and may shadow this one.")
+			fmt.Fprintln(cmd.OutOrStdout(), "    Remove it via your package manager, or remove ours with --uninstall.")
+			fmt.Fprintln(cmd.OutOrStdout(), "    arc-init never modifies files outside its managed paths, even with --force.")
 		}
 
-		if s.rcWritten {
-			fmt.Fprintln(cmd.OutOrStdout(), "  RC block: ADDED")
+		if s.rcStrategy != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  RC strategy: %s\n", s.rcStrategy)
+		}
+		if s.rcKind != "" && (s.rcWritten || s.rcMigrated || s.rcRolledBack) {
+			fmt.Fprintf(cmd.OutOrStdout(), "  RC file: %s (%s shells)\n", s.rcPath, s.rcKind)
+		}
+		if s.rcRolledBack {
+			fmt.Fprintf(cmd.OutOrStdout(), "  RC block: %s (another RC edit failed)\n", c.yellow("ROLLED BACK"))
+		} else if s.rcMigrated {
+			fmt.Fprintln(cmd.OutOrStdout(), "  RC block: "+c.green("MIGRATED")+" (legacy markers replaced)")
+		} else if s.rcWritten {
+			if s.rcUpdated {
+				fmt.Fprintln(cmd.OutOrStdout(), "  RC block: "+c.green("UPDATED")+" (replaced in place)")
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), "  RC block: "+c.green("ADDED"))
+			}
 		} else if s.rcSkipped {
-			fmt.Fprintf(cmd.OutOrStdout(), "  RC block: SKIPPED (%s)\n", s.reason)
+			fmt.Fprintf(cmd.OutOrStdout(), "  RC block: %s (%s)\n", c.yellow("SKIPPED"), s.rcReason)
+		}
+		if s.profilePath != "" && !s.profileRemoved {
+			fmt.Fprintf(cmd.OutOrStdout(), "  Login shell: %s (%s now sources .bashrc)\n", c.green("UPDATED"), s.profilePath)
 		}
 
 		fmt.Fprintln(cmd.OutOrStdout())
 	}
 
+	if dryRun {
+		fmt.Fprintln(cmd.OutOrStdout(), "No files were changed (dry-run). Re-run without --dry-run to apply.")
+		fmt.Fprintln(cmd.OutOrStdout())
+		fmt.Fprintln(cmd.OutOrStdout(), shellSummary(all, uninstalled))
+		return
+	}
+
 	fmt.Fprintln(cmd.OutOrStdout(), "Next steps:")
+	anySkipped, brewShell := false, false
+	for _, s := range statuses {
+		anySkipped = anySkipped || (s.skipped && !s.shellMissing)
+		_, ok := homebrewCompletionPaths[s.shell]
+		brewShell = brewShell || ok
+		if uninstalled || opts.nix || s.rcWritten || s.rcUnchanged || s.rcMigrated {
+			continue
+		}
+		hints := shellHints(s, opts)
+		if len(hints) == 0 {
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "  - %s: %s\n", s.shell, hints[0])
+		for _, line := range hints[1:] {
+			fmt.Fprintf(cmd.OutOrStdout(), "      %s\n", line)
+		}
+	}
+	if prefix := opts.paths.homebrewPrefix; prefix != "" && brewShell && !opts.homebrew && !opts.system && opts.outputDir == "" && !uninstalled {
+		fmt.Fprintf(cmd.OutOrStdout(), "  - Homebrew detected at %s; re-run with --homebrew to install into its completion directories\n", prefix)
+	}
+	if snippet := nixSnippet(statuses, opts); opts.nix && !uninstalled && len(snippet) > 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "  - add to your home-manager configuration (home.nix), then run home-manager switch:")
+		for _, line := range snippet {
+			fmt.Fprintf(cmd.OutOrStdout(), "      %s\n", line)
+		}
+	} else if !opts.nix && !opts.system && !uninstalled && nixDetected() {
+		fmt.Fprintln(cmd.OutOrStdout(), "  - Nix detected; re-run with --nix to install where home-manager looks and leave RC files to home.nix")
+	}
 	fmt.Fprintln(cmd.OutOrStdout(), "  - If completions not working, restart your shell")
-	fmt.Fprintln(cmd.OutOrStdout(), "  - Use --force to overwrite existing files")
-	fmt.Fprintln(cmd.OutOrStdout(), "  - Use --write-rc to update shell RC files")
+	if anySkipped && !uninstalled {
+		fmt.Fprintln(cmd.OutOrStdout(), "  - Use --force to overwrite existing files")
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout())
+	fmt.Fprintln(cmd.OutOrStdout(), shellSummary(all, uninstalled))
 }
 
-func writeBashCompletion(root *cobra.Command, force bool) (string, error) {
-	base := os.Getenv("XDG_CONFIG_HOME")
-	if base == "" {
-		home, _ := os.UserHomeDir()
-		base = filepath.Join(home, ".config")
+// shellSummary returns the one-line tally printed after the per-shell report,
+// e.g. "Summary: 3 installed, 1 skipped, 0 failed; RC: 2 added, 1 skipped, 0 removed".
+func shellSummary(statuses []shellStatus, uninstalled bool) string {
+	var done, unchanged, skipped, failed, rcAdded, rcSkipped, rcRemoved int
+	for _, s := range statuses {
+		switch {
+		case len(s.errs) > 0:
+			failed++
+		case s.written || s.completionRemoved:
+			done++
+		case s.unchanged:
+			unchanged++
+		case s.skipped:
+			skipped++
+		}
+		switch {
+		case s.rcRemoved:
+			rcRemoved++
+		case s.rcWritten:
+			rcAdded++
+		case s.rcSkipped:
+			rcSkipped++
+		}
 	}
-	dir := filepath.Join(base, "bash", "completions")
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", err
+
+	verb := "installed"
+	if uninstalled {
+		verb = "removed"
+	}
+	return fmt.Sprintf("Summary: %d %s, %d unchanged, %d skipped, %d failed; RC: %d added, %d skipped, %d removed",
+		done, verb, unchanged, skipped, failed, rcAdded, rcSkipped, rcRemoved)
+}
+
+func reportShellStatusJSON(cmd *cobra.Command, statuses []shellStatus) error {
+	entries := make([]shellStatusJSON, 0, len(statuses))
+	for _, s := range statuses {
+		entries = append(entries, s.toJSON())
 	}
-	path := filepath.Join(dir, "arc.bash")
-	if !force {
-		if _, err := os.Stat(path); err == nil {
-			return "", nil
+
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+func reportShellDryRun(cmd *cobra.Command, s shellStatus, uninstalled bool, c colorizer) {
+	out := cmd.OutOrStdout()
+
+	if uninstalled {
+		if s.completionRemoved {
+			fmt.Fprintf(out, "  Completions: %s %s (dry-run)\n", c.cyan("WOULD REMOVE"), s.path)
+		}
+		if s.rcRemoved {
+			fmt.Fprintf(out, "  RC block: %s from %s (dry-run)\n", c.cyan("WOULD REMOVE"), s.rcPath)
+			printRCDiff(out, s, c)
 		}
+		for _, b := range s.backupsRemoved {
+			fmt.Fprintf(out, "  RC backup: %s %s (dry-run)\n", c.cyan("WOULD REMOVE"), b)
+		}
+	} else if s.written {
+		fmt.Fprintf(out, "  Completions: %s %s (dry-run)\n", c.cyan("WOULD WRITE"), s.path)
+	} else if s.unchanged {
+		fmt.Fprintf(out, "  Completions: %s (content identical, not rewritten) (dry-run)\n", c.green("UNCHANGED"))
+	} else if s.shellMissing {
+		fmt.Fprintf(out, "  Completions: %s (%s) (dry-run)\n", c.yellow("SKIPPED"), s.reason)
+	} else if s.skipped {
+		fmt.Fprintf(out, "  Completions: %s (already exists, %s) (dry-run)\n", c.yellow("SKIPPED"), s.reason)
 	}
-	f, err := os.Create(path)
-	if err != nil {
-		return "", err
+	if s.written && s.descriptions != "" {
+		fmt.Fprintf(out, "  Descriptions: %s\n", s.descriptions)
 	}
-	defer f.Close()
-	if err := root.GenBashCompletion(f); err != nil {
-		return "", err
+
+	if s.rcStrategy != "" {
+		fmt.Fprintf(out, "  RC strategy: %s\n", s.rcStrategy)
+	}
+	if s.rcMigrated {
+		fmt.Fprintf(out, "  RC block: %s legacy block in %s (dry-run)\n", c.cyan("WOULD MIGRATE"), s.rcPath)
+		printRCDiff(out, s, c)
+	} else if s.rcWritten {
+		if s.rcUpdated {
+			fmt.Fprintf(out, "  RC block: %s in %s (dry-run)\n", c.cyan("WOULD UPDATE"), s.rcPath)
+		} else {
+			fmt.Fprintf(out, "  RC block: %s to %s (dry-run)\n", c.cyan("WOULD APPEND"), s.rcPath)
+		}
+		printRCDiff(out, s, c)
+	} else if s.rcSkipped {
+		fmt.Fprintf(out, "  RC block: %s (%s) (dry-run)\n", c.yellow("SKIPPED"), s.rcReason)
+	}
+	if s.profileRemoved {
+		fmt.Fprintf(out, "  Login shell: %s block from %s (dry-run)\n", c.cyan("WOULD REMOVE"), s.profilePath)
+	} else if s.profilePath != "" {
+		fmt.Fprintf(out, "  Login shell: %s %s to source .bashrc (dry-run)\n", c.cyan("WOULD UPDATE"), s.profilePath)
 	}
-	return path, nil
 }
 
-func writeZshCompletion(root *cobra.Command, force bool) (string, error) {
-	home, _ := os.UserHomeDir()
-	dir := filepath.Join(home, ".zsh", "completions")
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", err
+// printRCDiff shows the change a dry run would make to s.rcPath, falling
+// back to the block itself when no diff was computed.
+func printRCDiff(out io.Writer, s shellStatus, c colorizer) {
+	text := s.rcDiff
+	if text == "" {
+		text = s.rcBlock
 	}
-	path := filepath.Join(dir, "_arc")
-	if !force {
-		if _, err := os.Stat(path); err == nil {
-			return "", nil
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		switch {
+		case s.rcDiff == "":
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			line = c.green(line)
+		case strings.HasPrefix(line, "-"):
+			line = c.red(line)
+		case strings.HasPrefix(line, "@@"):
+			line = c.cyan(line)
 		}
+		fmt.Fprintf(out, "    %s\n", line)
+	}
+}
+
+// rcRemovalDiff returns the diff removeRCBlock would apply to path.
+func rcRemovalDiff(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	updated, ok := blockedit.Cut(string(data), rcStart, rcEnd)
+	if !ok {
+		return ""
+	}
+	return blockedit.Diff(path, string(data), updated)
+}
+
+// writeCompletion writes script to shell's completion path, creating the
+// directory as needed, and returns the path. Shells differ only in the
+// completionPath table; unknown ones yield ErrUnsupportedShell.
+func writeCompletion(shell string, script []byte, opts shellOptions) (string, error) {
+	path, err := completionPath(shell, opts)
+	if err != nil {
+		return "", err
 	}
-	f, err := os.Create(path)
+	dir := filepath.Dir(path)
+	err = mkdirAll(dir, opts.logger())
+	opts.logger().Debug("mkdir", "path", dir, "err", err)
 	if err != nil {
 		return "", err
 	}
-	defer f.Close()
-	if err := root.GenZshCompletion(f); err != nil {
+	if err := writeCompletionFile(path, script, opts.logger()); err != nil {
 		return "", err
 	}
 	return path, nil
 }
 
-func writeFishCompletion(root *cobra.Command, force bool) (string, error) {
-	base := os.Getenv("XDG_CONFIG_HOME")
-	if base == "" {
-		home, _ := os.UserHomeDir()
-		base = filepath.Join(home, ".config")
+// renameFile is os.Rename; tests swap it to make the final step of
+// writeCompletionFile fail.
+var renameFile = os.Rename
+
+// writeCompletionFile atomically replaces path with script: the content is
+// written to a temp file in the same directory and renamed into place, so an
+// interrupted write never leaves a truncated completion file behind.
+func writeCompletionFile(path string, script []byte, log *slog.Logger) error {
+	var tmp *os.File
+	err := fsRetry.do(log, "create", path, func() (err error) {
+		tmp, err = os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+		return err
+	})
+	log.Debug("create temp file", "dir", filepath.Dir(path), "err", err)
+	if err != nil {
+		return err
 	}
-	dir := filepath.Join(base, "fish", "completions")
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", err
+	tmpName := tmp.Name()
+
+	fail := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpName)
+		log.Debug("write completion file failed", "path", path, "temp", tmpName, "err", err)
+		return err
 	}
-	path := filepath.Join(dir, "arc.fish")
-	if !force {
-		if _, err := os.Stat(path); err == nil {
-			return "", nil
+
+	if _, err := tmp.Write(script); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fail(err)
+	}
+	if err := os.Chmod(tmpName, 0o644); err != nil {
+		return fail(err)
+	}
+	if err := fsRetry.do(log, "rename", path, func() error { return renameFile(tmpName, path) }); err != nil {
+		return fail(err)
+	}
+	log.Debug("rename", "from", tmpName, "to", path, "bytes", len(script))
+	return nil
+}
+
+// supportedShells lists the shells arc-init can install completions for, in
+// the order they are processed and reported.
+var supportedShells = []string{"bash", "zsh", "fish", "powershell", "nushell", "elvish", "xonsh", "tcsh"}
+
+// allShells returns the shells selected by --all on goos. shellEnv is the
+// SHELL variable, used on Windows to detect a POSIX layer.
+func allShells(goos, shellEnv string) []string {
+	if goos == "windows" {
+		shells := []string{"powershell"}
+		if shellEnv != "" {
+			shells = append(shells, "bash", "zsh")
+		}
+		return shells
+	}
+	return []string{"bash", "zsh", "fish", "nushell", "elvish", "xonsh"}
+}
+
+// completionFileNames maps each shell to the file name of its completion script.
+var completionFileNames = map[string]string{
+	"bash":       "arc.bash",
+	"zsh":        "_arc",
+	"fish":       "arc.fish",
+	"powershell": "arc.ps1",
+	"nushell":    "arc.nu",
+	"elvish":     "arc.elv",
+	"xonsh":      "arc.py",
+	"tcsh":       "arc.tcsh",
+}
+
+// completionFileName returns the file name of shell's completion script,
+// named after --command-name when one is set (e.g. _foo instead of _arc).
+func completionFileName(shell string, opts shellOptions) string {
+	// bash-completion and fish load files under $XDG_DATA_HOME on demand,
+	// looking them up by the command being completed.
+	if opts.usesDataHome(shell) {
+		switch shell {
+		case "bash":
+			return opts.command()
+		case "fish":
+			return opts.command() + ".fish"
 		}
 	}
-	f, err := os.Create(path)
+	return withCommandName(completionFileNames[shell], opts.commandName)
+}
+
+// usesDataHome reports whether shell's completion goes under $XDG_DATA_HOME:
+// always with --xdg-data, and otherwise when that directory already exists
+// and nothing is installed at the $XDG_CONFIG_HOME location, so existing
+// installs never move on their own.
+func (o shellOptions) usesDataHome(shell string) bool {
+	if o.outputDir != "" || o.system || o.homebrew {
+		return false
+	}
+	dir, ok := o.paths.dataCompletionDir(shell)
+	if !ok {
+		return false
+	}
+	if o.xdgData {
+		return true
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return false
+	}
+	configPath := filepath.Join(configCompletionDir(shell, o), withCommandName(completionFileNames[shell], o.commandName))
+	_, err := os.Stat(configPath)
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// withCommandName swaps the "arc" in a completion file name for name.
+func withCommandName(file, name string) string {
+	if name == "" {
+		return file
+	}
+	return strings.Replace(file, "arc", name, 1)
+}
+
+// systemCompletionPaths are the system-wide completion locations used by
+// --system, per OS. Each shell loads them for every user without an RC block.
+var systemCompletionPaths = map[string]map[string]string{
+	"linux": {
+		"bash": "/etc/bash_completion.d/arc",
+		"zsh":  "/usr/local/share/zsh/site-functions/_arc",
+		"fish": "/usr/share/fish/vendor_completions.d/arc.fish",
+	},
+	"darwin": {
+		"bash": "/usr/local/etc/bash_completion.d/arc",
+		"zsh":  "/usr/local/share/zsh/site-functions/_arc",
+		"fish": "/usr/local/share/fish/vendor_completions.d/arc.fish",
+	},
+}
+
+// systemCompletionPath returns the --system location for shell. BSDs share
+// the Linux layout; Windows has none.
+func (p pathContext) systemCompletionPath(shell string) (string, bool) {
+	table, ok := systemCompletionPaths[p.goos]
+	if !ok && p.goos != "windows" {
+		table = systemCompletionPaths["linux"]
+	}
+	path, ok := table[shell]
+	return path, ok
+}
+
+// homebrewCompletionPaths are the completion locations under
+// $HOMEBREW_PREFIX used by --homebrew. Homebrew's bash-completion, zsh, and
+// fish read them without further setup.
+var homebrewCompletionPaths = map[string]string{
+	"bash": filepath.Join("etc", "bash_completion.d", "arc"),
+	"zsh":  filepath.Join("share", "zsh", "site-functions", "_arc"),
+	"fish": filepath.Join("share", "fish", "vendor_completions.d", "arc.fish"),
+}
+
+// homebrewCompletionPath returns the --homebrew location for shell.
+func (p pathContext) homebrewCompletionPath(shell string) (string, bool) {
+	rel, ok := homebrewCompletionPaths[shell]
+	if !ok || p.homebrewPrefix == "" {
+		return "", false
+	}
+	return filepath.Join(p.homebrewPrefix, rel), true
+}
+
+// completionScript returns the file contents installed for shell at path:
+// completionBody plus the version header.
+func completionScript(root *cobra.Command, shell, path string, opts shellOptions) ([]byte, error) {
+	script, err := completionBody(root, shell, path, opts)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer f.Close()
-	if err := root.GenFishCompletion(f, true); err != nil {
-		return "", err
+	return withCompletionHeader(script, completionHeader(shell, time.Now())), nil
+}
+
+// completionBody is the generated script for shell (without descriptions
+// under --no-descriptions) with any --alias registrations, rendered through
+// --template-file when one is given.
+func completionBody(root *cobra.Command, shell, path string, opts shellOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	gen := genOptions{descriptions: !opts.noDescriptions, name: opts.command(), staticBash: opts.staticBash, includeHidden: opts.includeHidden}
+	if err := generateScript(root, shell, &buf, gen); err != nil {
+		return nil, err
 	}
-	return path, nil
+	if err := checkGeneratedScript(buf.Bytes(), gen.name); err != nil {
+		return nil, fmt.Errorf("%s: %w", shell, err)
+	}
+	script := withAliases(shell, buf.Bytes(), opts.command(), opts.aliases)
+	return applyCompletionTemplate(opts.completionTemplate, script, shell, path, opts)
 }
 
-func writePSCompletion(root *cobra.Command, force bool) (string, error) {
-	base := os.Getenv("XDG_CONFIG_HOME")
-	if base == "" {
-		home, _ := os.UserHomeDir()
-		base = filepath.Join(home, ".config")
+// checkGeneratedScript rejects a generated script that is blank or never
+// mentions the command it completes, which is what a cobra regression or a
+// misconfigured root command produces, so such a file is never installed.
+func checkGeneratedScript(script []byte, name string) error {
+	if len(bytes.TrimSpace(script)) == 0 {
+		return ErrEmptyCompletion
 	}
-	dir := filepath.Join(base, "powershell")
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", err
+	if !bytes.Contains(script, []byte(name)) {
+		return fmt.Errorf("%w: no reference to %q", ErrEmptyCompletion, name)
+	}
+	return nil
+}
+
+// completionDir returns the directory a shell's completion script is written
+// to, honoring --output-dir, --system, --homebrew, and --xdg-data when set.
+// Shells without a Homebrew or data-home location keep their usual directory.
+func completionDir(shell string, opts shellOptions) string {
+	if opts.outputDir != "" {
+		return opts.outputDir
+	}
+	if opts.system {
+		if path, ok := opts.paths.systemCompletionPath(shell); ok {
+			return filepath.Dir(path)
+		}
+		return ""
+	}
+	if opts.homebrew {
+		if path, ok := opts.paths.homebrewCompletionPath(shell); ok {
+			return filepath.Dir(path)
+		}
+	}
+	if opts.usesDataHome(shell) {
+		dir, _ := opts.paths.dataCompletionDir(shell)
+		return dir
 	}
-	path := filepath.Join(dir, "arc.ps1")
-	if !force {
-		if _, err := os.Stat(path); err == nil {
-			return "", nil
+	return configCompletionDir(shell, opts)
+}
+
+// configCompletionDir is the default per-user completion directory for shell,
+// mostly under $XDG_CONFIG_HOME.
+func configCompletionDir(shell string, opts shellOptions) string {
+	switch shell {
+	case "bash":
+		if opts.paths.msys() {
+			// Git Bash has no ~/.config convention of its own.
+			return filepath.Join(opts.paths.home, ".bash_completion.d")
+		}
+		return filepath.Join(opts.paths.configHome, "bash", "completions")
+	case "zsh":
+		if opts.paths.ohMyZsh != "" {
+			return filepath.Join(opts.paths.ohMyZsh, "completions")
 		}
+		return filepath.Join(opts.paths.zshDir(), ".zsh", "completions")
+	case "fish":
+		return opts.fishCompletionDir()
+	case "powershell":
+		return filepath.Join(opts.paths.configHome, "powershell")
+	case "nushell":
+		return filepath.Join(opts.paths.configHome, "nushell", "completions")
+	case "elvish":
+		return filepath.Join(opts.paths.configHome, "elvish", "lib")
+	case "xonsh":
+		return filepath.Join(opts.paths.configHome, "xonsh", "completions")
+	case "tcsh":
+		return filepath.Join(opts.paths.configHome, "tcsh", "completions")
+	}
+	return ""
+}
+
+// completionPath returns the full path of a shell's completion script.
+func completionPath(shell string, opts shellOptions) (string, error) {
+	if _, ok := completionFileNames[shell]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedShell, shell)
+	}
+	if opts.system && opts.outputDir == "" {
+		path, ok := opts.paths.systemCompletionPath(shell)
+		if !ok {
+			return "", fmt.Errorf("no system-wide completion location for %s", shell)
+		}
+		return filepath.Join(filepath.Dir(path), withCommandName(filepath.Base(path), opts.commandName)), nil
+	}
+	if opts.homebrew && opts.outputDir == "" {
+		if path, ok := opts.paths.homebrewCompletionPath(shell); ok {
+			return filepath.Join(filepath.Dir(path), withCommandName(filepath.Base(path), opts.commandName)), nil
+		}
+	}
+	return filepath.Join(completionDir(shell, opts), completionFileName(shell, opts)), nil
+}
+
+// generateMu guards the command tree, which cobra's generators walk and may
+// initialize lazily.
+var generateMu sync.Mutex
+
+// GenerateCompletion writes the completion script for shell to w without
+// touching the filesystem. It is the single generation path behind every
+// install and print; callers embedding arc-init can use it to capture scripts
+// and place them themselves. root is the command tree to complete, normally
+// the one returned by NewRootCmd; the script binds to arc, the name the
+// binary is invoked as, like the installed files. Unknown shells yield
+// ErrUnsupportedShell.
+func GenerateCompletion(root *cobra.Command, shell string, w io.Writer) error {
+	return generateCompletionScript(root, shell, w, genOptions{descriptions: true, name: completionCommandName})
+}
+
+// supportsDescriptions reports whether --no-descriptions changes the script
+// generated for shell. Static bash scripts never carry descriptions.
+func supportsDescriptions(shell string) bool {
+	return shell == "bash" || shell == "zsh" || shell == "fish" || shell == "powershell"
+}
+
+// genOptions tunes generateCompletionScript.
+type genOptions struct {
+	// descriptions keeps candidate descriptions for bash, zsh, fish, and
+	// PowerShell.
+	descriptions bool
+	// name generates the script for a binary installed under that name
+	// instead of root's own.
+	name string
+	// staticBash bakes the command tree into the bash script (cobra's v1
+	// generator) instead of asking the binary for candidates through
+	// __complete. Every other shell's script is always dynamic.
+	staticBash bool
+	// includeHidden generates the static bash script from a copy of the
+	// tree with hidden commands made visible.
+	includeHidden bool
+}
+
+// generateScript is the generator completionBody installs from. It is a
+// variable so tests can stand in a generator that misbehaves.
+var generateScript = generateCompletionScript
+
+// generateCompletionScript is GenerateCompletion with the knobs in gen.
+func generateCompletionScript(root *cobra.Command, shell string, w io.Writer, gen genOptions) error {
+	generateMu.Lock()
+	defer generateMu.Unlock()
+
+	if name := gen.name; name != "" && name != root.Name() {
+		use := root.Use
+		root.Use = name + strings.TrimPrefix(use, root.Name())
+		defer func() { root.Use = use }()
+	}
+	descriptions := gen.descriptions
+
+	switch shell {
+	case "bash":
+		if gen.staticBash {
+			if gen.includeHidden {
+				return unhiddenTree(root).GenBashCompletion(w)
+			}
+			return root.GenBashCompletion(w)
+		}
+		return root.GenBashCompletionV2(w, descriptions)
+	case "zsh":
+		if !descriptions {
+			return root.GenZshCompletionNoDesc(w)
+		}
+		return root.GenZshCompletion(w)
+	case "fish":
+		return root.GenFishCompletion(w, descriptions)
+	case "powershell":
+		if !descriptions {
+			return root.GenPowerShellCompletion(w)
+		}
+		return root.GenPowerShellCompletionWithDesc(w)
+	case "nushell":
+		return genNushellCompletion(root, w)
+	case "elvish":
+		return genElvishCompletion(root, w)
+	case "xonsh":
+		return genXonshCompletion(root, w)
+	case "tcsh":
+		return genTcshCompletion(root, w)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedShell, shell)
+}
+
+// ensureWritableDir verifies that dir exists (creating it unless dryRun is
+// set) and that a file can be created inside it.
+func ensureWritableDir(dir string, dryRun bool, log *slog.Logger) error {
+	info, err := os.Stat(dir)
+	log.Debug("stat output directory", "path", dir, "err", err)
+	if errors.Is(err, os.ErrNotExist) {
+		if dryRun {
+			return nil
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			log.Debug("mkdir", "path", dir, "err", err)
+			return fmt.Errorf("output directory %s is not writable: %w", dir, err)
+		}
+		log.Debug("mkdir", "path", dir)
+		info, err = os.Stat(dir)
 	}
-	f, err := os.Create(path)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("output directory %s: %w", dir, err)
 	}
-	defer f.Close()
-	if err := root.GenPowerShellCompletionWithDesc(f); err != nil {
-		return "", err
+	if !info.IsDir() {
+		return fmt.Errorf("output directory %s is not a directory", dir)
 	}
-	return path, nil
+
+	f, err := os.CreateTemp(dir, ".arc-write-check-*")
+	log.Debug("create write probe", "dir", dir, "err", err)
+	if err != nil {
+		return fmt.Errorf("output directory %s is not writable: %w", dir, err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
 }
 
 const rcStart = "# >>> arc init >>>"
 const rcEnd = "# <<< arc init <<<"
 
-func detectShell() string {
-	sh := os.Getenv("SHELL")
+// parentProcessName returns the command name of the parent process, giving
+// up on ps after timeout. It is a variable so detection can be exercised
+// without a real process tree.
+var parentProcessName = lookupParentProcessName
+
+// detectShell returns the user's shell, trusting SHELL first and falling back
+// to the parent process name when SHELL is empty or unrecognized.
+func detectShell(timeout time.Duration) string {
+	return detectShellFrom(os.Getenv("SHELL"), func() string { return parentProcessName(timeout) })
+}
+
+func detectShellFrom(shellEnv string, parent func() string) string {
+	if sh := shellFromName(shellEnv); sh != "" {
+		return sh
+	}
+	if parent != nil {
+		return shellFromName(parent())
+	}
+	return ""
+}
+
+// shellFromName maps a shell path or process name to a supported shell.
+func shellFromName(name string) string {
+	sh := strings.ToLower(name)
 	if strings.Contains(sh, "zsh") {
 		return "zsh"
 	}
@@ -366,62 +1990,70 @@ func detectShell() string {
 	if strings.Contains(sh, "fish") {
 		return "fish"
 	}
-	if strings.Contains(strings.ToLower(sh), "powershell") {
+	if strings.Contains(sh, "powershell") || strings.Contains(filepath.Base(sh), "pwsh") {
 		return "powershell"
 	}
+	if filepath.Base(sh) == "nu" || strings.Contains(sh, "nushell") {
+		return "nushell"
+	}
+	if strings.Contains(sh, "elvish") {
+		return "elvish"
+	}
+	if strings.Contains(sh, "xonsh") {
+		return "xonsh"
+	}
+	if base := filepath.Base(sh); base == "tcsh" || base == "csh" {
+		return "tcsh"
+	}
 	return ""
 }
 
-func bashRCPath() string {
-	home, _ := os.UserHomeDir()
-	rc := filepath.Join(home, ".bashrc")
-	if _, err := os.Stat(rc); errors.Is(err, os.ErrNotExist) {
-		return filepath.Join(home, ".bash_profile")
+func lookupParentProcessName(timeout time.Duration) string {
+	ppid := os.Getppid()
+	if ppid <= 1 {
+		return ""
 	}
-	return rc
+
+	switch runtime.GOOS {
+	case "linux":
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", ppid))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimPrefix(strings.TrimSpace(string(data)), "-")
+	case "darwin", "freebsd", "openbsd", "netbsd", "dragonfly":
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, "ps", "-o", "comm=", "-p", strconv.Itoa(ppid)).Output()
+		if err != nil {
+			return ""
+		}
+		return filepath.Base(strings.TrimPrefix(strings.TrimSpace(string(out)), "-"))
+	}
+	return ""
 }
 
-func zshRCPath() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".zshrc")
+// rcTarget returns the file an RC path refers to. A symlinked RC file (e.g.
+// into a dotfiles repo) is refused unless --follow-symlinks is set, in which
+// case the real target is returned so edits and backups land next to it.
+func (o shellOptions) rcTarget(path string) (string, error) {
+	target, err := blockedit.Resolve(path, o.followSymlinks)
+	o.logger().Debug("resolve RC path", "path", path, "target", target, "err", err)
+	return target, err
 }
 
-func removeRCBlock(path string) error {
-	b, err := os.ReadFile(path)
-	if err != nil {
-		return err
-	}
-	s := string(b)
-	start := strings.Index(s, rcStart)
-	end := strings.Index(s, rcEnd)
-	if start == -1 || end == -1 || end < start {
-		return nil
-	}
-	end += len(rcEnd)
-	s2 := strings.TrimSpace(s[:start]+s[end:]) + "\n"
-	return os.WriteFile(path, []byte(s2), 0o644)
+// currentRCMarkers delimits the blocks arc writes today.
+var currentRCMarkers = rcMarkers{start: rcStart, end: rcEnd}
+
+// removeRCBlock deletes the arc block from path, backing the file up first,
+// and reports whether there was one to delete.
+func removeRCBlock(path string, dryRun bool, keepBackups int, log *slog.Logger) (bool, error) {
+	return blockedit.Remove(path, rcStart, rcEnd, blockedit.Options{DryRun: dryRun, KeepBackups: keepBackups, Log: log})
 }
 
-func upsertRCBlock(path, block string, force bool) error {
-	var cur string
-	if _, err := os.Stat(path); err == nil {
-		b, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-		cur = string(b)
-		if strings.Contains(cur, rcStart) && strings.Contains(cur, rcEnd) {
-			return nil
-		}
-		if !force {
-			_ = os.WriteFile(path+".arc.bak", b, 0o644)
-		}
-	}
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	_, err = f.WriteString("\n" + block)
+// upsertRCBlock adds block to path; with replace, an existing block that
+// differs is rewritten in place.
+func upsertRCBlock(path, block string, replace bool, keepBackups int, log *slog.Logger) error {
+	_, err := blockedit.Upsert(path, rcStart, rcEnd, block, blockedit.Options{Replace: replace, KeepBackups: keepBackups, Log: log})
 	return err
 }
@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWriteCompletionFileFailureKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "arc.bash")
+	original := []byte("# arc-init v1.0.0\ncomplete -F _arc arc\n")
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	errRename := errors.New("rename failed")
+	renameFile = func(string, string) error { return errRename }
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	err := writeCompletionFile(path, []byte("# arc-init v2.0.0\n"), shellOptions{}.logger())
+	if !errors.Is(err, errRename) {
+		t.Fatalf("writeCompletionFile error = %v, want %v", err, errRename)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, original) {
+		t.Errorf("original file changed:\ngot  %q\nwant %q", got, original)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("temp file left behind: %v", names)
+	}
+}
+
+func TestRCBlockForFollowsXDGConfigHome(t *testing.T) {
+	home, xdg := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "no-data-home"))
+	t.Setenv("MSYSTEM", "")
+	opts := shellOptions{paths: newPathContext("")}
+
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"bash", filepath.Join(xdg, "bash", "completions", "arc.bash")},
+		{"fish", filepath.Join(xdg, "fish", "completions")},
+		{"powershell", filepath.Join(xdg, "powershell", "arc.ps1")},
+		{"xonsh", filepath.Join(xdg, "xonsh", "completions", "arc.py")},
+		{"tcsh", filepath.Join(xdg, "tcsh", "completions", "arc.tcsh")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			_, block, err := rcBlockFor(tt.shell, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(block, tt.want) {
+				t.Errorf("block does not reference %s:\n%s", tt.want, block)
+			}
+		})
+	}
+}
+
+func TestEnsureShellRCReplacesStaleBlockInPlace(t *testing.T) {
+	home := t.TempDir()
+	rc := filepath.Join(home, ".bashrc")
+	before := "export PATH=$HOME/bin:$PATH\nalias ll='ls -l'\n"
+	after := "alias g=git\nexport EDITOR=vi\n"
+	stale := rcStart + "\n# Arc bash completions\n. /old/path/arc.bash\n" + rcEnd + "\n"
+	if err := os.WriteFile(rc, []byte(before+stale+after), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := shellOptions{
+		forceRC: true,
+		paths:   pathContext{goos: "linux", home: home, configHome: filepath.Join(home, ".config"), zdotdir: home},
+	}
+	_, block, err := rcBlockFor("bash", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var status shellStatus
+	if err := ensureShellRC(&status, "bash", opts); err != nil {
+		t.Fatal(err)
+	}
+	if !status.rcWritten || !status.rcUpdated {
+		t.Errorf("rcWritten = %v, rcUpdated = %v; want both", status.rcWritten, status.rcUpdated)
+	}
+	got, err := os.ReadFile(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := before + block + after; string(got) != want {
+		t.Errorf("RC file:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteShellCompletionSkipsIdenticalContent(t *testing.T) {
+	home := t.TempDir()
+	opts := shellOptions{
+		force: true,
+		paths: pathContext{goos: "linux", home: home, configHome: filepath.Join(home, ".config"), zdotdir: home},
+	}
+	root := NewRootCmd()
+
+	var first shellStatus
+	if err := writeShellCompletion(&first, root, "bash", opts); err != nil {
+		t.Fatal(err)
+	}
+	if !first.written {
+		t.Fatal("first write not reported as written")
+	}
+	// Backdate the file so a rewrite would show in its mtime.
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(first.path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.Stat(first.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var second shellStatus
+	if err := writeShellCompletion(&second, root, "bash", opts); err != nil {
+		t.Fatal(err)
+	}
+	if !second.unchanged || second.written {
+		t.Errorf("unchanged = %v, written = %v; want true, false", second.unchanged, second.written)
+	}
+	after, err := os.Stat(first.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(before, after) {
+		t.Error("file was replaced (inode changed)")
+	}
+	if !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("mtime changed from %v to %v", before.ModTime(), after.ModTime())
+	}
+}
+
+func TestDetectionUsesShellTimeout(t *testing.T) {
+	var got time.Duration
+	parentProcessName = func(timeout time.Duration) string {
+		got = timeout
+		return "zsh"
+	}
+	t.Cleanup(func() { parentProcessName = lookupParentProcessName })
+
+	opts := shellOptions{forceDetect: true, timeout: 250 * time.Millisecond}
+	if sh := opts.currentShell(); sh != "zsh" {
+		t.Errorf("currentShell() = %q, want zsh", sh)
+	}
+	if got != opts.timeout {
+		t.Errorf("parent process lookup timeout = %s, want %s", got, opts.timeout)
+	}
+}
+
+func TestEmptyGeneratedScriptIsNotInstalled(t *testing.T) {
+	generateScript = func(*cobra.Command, string, io.Writer, genOptions) error { return nil }
+	t.Cleanup(func() { generateScript = generateCompletionScript })
+
+	dir := t.TempDir()
+	root := &cobra.Command{Use: "arc"}
+	opts := shellOptions{force: true, outputDir: dir, paths: pathContext{goos: "linux", home: t.TempDir()}}
+	for _, shell := range supportedShells {
+		var status shellStatus
+		if err := writeShellCompletion(&status, root, shell, opts); !errors.Is(err, ErrEmptyCompletion) {
+			t.Errorf("%s: error = %v, want ErrEmptyCompletion", shell, err)
+		}
+		if status.written {
+			t.Errorf("%s: reported as written", shell)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("files written: %v", entries)
+	}
+}
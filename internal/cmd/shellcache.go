@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// shellCache records the outcome of the last successful install so that a
+// repeated run with the same flags can return after a few stat calls instead
+// of regenerating every script. It is an optimization only: a missing or
+// unreadable cache just means taking the full path.
+type shellCache struct {
+	// Key identifies the arc-init build, flags, and environment the cached
+	// run used.
+	Key   string       `json:"key"`
+	Files []cachedFile `json:"files"`
+}
+
+// cachedFile is the state of a file the cached run wrote or read. Missing
+// records that the file did not exist, so creating it invalidates the cache.
+type cachedFile struct {
+	Path    string    `json:"path"`
+	Missing bool      `json:"missing,omitempty"`
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"mod_time,omitempty"`
+}
+
+func shellCachePath(p pathContext) string {
+	return filepath.Join(filepath.Dir(shellManifestPath(p)), "shell-cache.json")
+}
+
+// shellCacheKey hashes everything besides file contents that decides what an
+// install writes: the version, the flags given, and the resolved paths.
+func shellCacheKey(flags *pflag.FlagSet, p pathContext) string {
+	var set []string
+	flags.Visit(func(f *pflag.Flag) {
+		if f.Name != "refresh" {
+			set = append(set, f.Name+"="+f.Value.String())
+		}
+	})
+	sort.Strings(set)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%q\n%#v\n%s\n", version, set, p, os.Getenv("SHELL"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func statCachedFile(path string) cachedFile {
+	info, err := os.Stat(path)
+	if err != nil {
+		return cachedFile{Path: path, Missing: true}
+	}
+	return cachedFile{Path: path, Size: info.Size(), ModTime: info.ModTime().UTC()}
+}
+
+// shellCacheCurrent reports whether the cache at path was written for key and
+// every file it lists is as the cached run left it.
+func shellCacheCurrent(path, key string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var c shellCache
+	if err := json.Unmarshal(data, &c); err != nil || c.Key != key {
+		return false
+	}
+	for _, f := range c.Files {
+		now := statCachedFile(f.Path)
+		if now.Missing != f.Missing || now.Size != f.Size || !now.ModTime.Equal(f.ModTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// saveShellCache records statuses under key, along with inputs (the config
+// file, the arc-init binary, and so on) whose changes should also force the
+// full path. Any failed shell, or one whose RC change was declined at the
+// prompt, removes the cache instead, so the next run tries again.
+func saveShellCache(path, key string, statuses []shellStatus, inputs []string) error {
+	paths := append([]string(nil), inputs...)
+	for _, s := range statuses {
+		if len(s.errs) > 0 || s.rcReason == rcDeclinedReason {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			return nil
+		}
+		for _, p := range append([]string{s.path, s.rcPath, s.profilePath}, s.aliasPaths...) {
+			if p != "" {
+				paths = append(paths, p)
+			}
+		}
+	}
+
+	c := shellCache{Key: key}
+	for _, p := range paths {
+		c.Files = append(c.Files, statCachedFile(p))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// shellCacheInputs are the files besides those written that an install
+// depends on.
+func shellCacheInputs(p pathContext, templateFile string) []string {
+	var inputs []string
+	if exe, err := os.Executable(); err == nil {
+		inputs = append(inputs, exe)
+	}
+	if cfg, err := systemConfigFile(p); err == nil {
+		inputs = append(inputs, cfg)
+	}
+	if templateFile != "" {
+		inputs = append(inputs, templateFile)
+	}
+	return inputs
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveShellCacheSkipsIncompleteRuns(t *testing.T) {
+	dir := t.TempDir()
+	completion := filepath.Join(dir, "arc.bash")
+	if err := os.WriteFile(completion, []byte("complete -F _arc arc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		status shellStatus
+		want   bool
+	}{
+		{"installed", shellStatus{shell: "bash", path: completion, written: true}, true},
+		{"failed", shellStatus{shell: "bash", path: completion, errs: []string{"boom"}}, false},
+		{"RC declined", shellStatus{shell: "bash", path: completion, written: true, rcSkipped: true, rcReason: rcDeclinedReason}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "shell-cache.json")
+			if err := saveShellCache(path, "key", []shellStatus{tt.status}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got := shellCacheCurrent(path, "key"); got != tt.want {
+				t.Errorf("cache current = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// shellConfig is the shell section of the global config.yaml:
+//
+//	shell:
+//	  install: [bash, zsh]
+//	  write_rc: true
+type shellConfig struct {
+	Install []string `yaml:"install"`
+	WriteRC *bool    `yaml:"write_rc"`
+}
+
+func systemConfigFile(p pathContext) (string, error) {
+	dir, err := p.systemConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// loadShellConfig reads the shell section of the global config. A missing
+// config file yields an empty shellConfig.
+func loadShellConfig(p pathContext) (shellConfig, error) {
+	path, err := systemConfigFile(p)
+	if err != nil {
+		return shellConfig{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return shellConfig{}, nil
+	}
+	if err != nil {
+		return shellConfig{}, err
+	}
+
+	var doc struct {
+		Shell shellConfig `yaml:"shell"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return shellConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, sh := range doc.Shell.Install {
+		if _, ok := completionFileNames[sh]; !ok {
+			return shellConfig{}, fmt.Errorf("%s: unknown shell %q in shell.install", path, sh)
+		}
+	}
+	return doc.Shell, nil
+}
+
+// saveShellConfig sets shell.install in the global config to shells, keeping
+// the rest of the file (including comments) intact. It returns the path
+// written.
+func saveShellConfig(p pathContext, shells []string) (string, error) {
+	path, err := systemConfigFile(p)
+	if err != nil {
+		return "", err
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("%s: top level is not a mapping", path)
+	}
+
+	section := mappingValue(root, "shell")
+	if section == nil || section.Kind != yaml.MappingNode {
+		section = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(root, "shell", section)
+	}
+	install := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, sh := range shells {
+		install.Content = append(install.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: sh})
+	}
+	setMappingValue(section, "install", install)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
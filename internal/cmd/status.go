@@ -0,0 +1,323 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-init/internal/blockedit"
+	"gopkg.in/yaml.v3"
+)
+
+// Footprint states besides driftCurrent, driftStale, and driftMissing.
+const (
+	// footprintForeign is a file at an arc-managed path without the
+	// arc-init header, e.g. one from a package manager.
+	footprintForeign = "foreign"
+	// footprintLegacy is an RC block with pre-migration markers.
+	footprintLegacy = "legacy"
+	// footprintInvalid is a config file that does not parse or validate.
+	footprintInvalid = "invalid"
+	// footprintNewer is a config with a schema newer than this build's.
+	footprintNewer = "newer"
+)
+
+// footprintItem is one file, or block within a file, that arc-init manages.
+type footprintItem struct {
+	// Kind is completion, rc_block, manifest, system_config, or
+	// project_config.
+	Kind  string `json:"kind"`
+	Shell string `json:"shell,omitempty"`
+	Path  string `json:"path"`
+	// Version is the arc-init version in a completion header, or the
+	// schema version of a config file.
+	Version string `json:"version,omitempty"`
+	State   string `json:"state"`
+	Note    string `json:"note,omitempty"`
+}
+
+func newStatusCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "List everything arc-init has installed",
+		Long: `List everything arc-init has installed and whether it is current: shell
+completion files, RC blocks, the install manifest, the global config
+(~/.config/arc/config.yaml), and the config of the project in the current
+directory (.arc/config.yaml).
+
+Completion files are looked for at the default location of every shell and
+at every path recorded in the install manifest. Each shows the arc-init
+version in its header and whether it matches what this binary generates;
+RC blocks are compared with the block arc-init shell would write with
+default flags, so blocks from --rc-file or similar flags may show as stale.
+Configs show their schema version and whether they validate.
+
+status never writes anything; use doctor to diagnose completions that do not
+work, and arc-init shell --check to gate CI on drift.`,
+		Example: `  arc-init status
+  arc-init status --json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items := collectFootprint(cmd.Root(), pathsFrom(cmd.Context()))
+			if jsonOutput {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(struct {
+					Version string          `json:"version"`
+					Items   []footprintItem `json:"items"`
+				}{version, items})
+			}
+			reportFootprint(cmd, items)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the inventory as JSON")
+
+	return cmd
+}
+
+// collectFootprint inspects every location arc-init manages, read-only.
+func collectFootprint(root *cobra.Command, p pathContext) []footprintItem {
+	opts := shellOptions{paths: p}
+	var items []footprintItem
+
+	manifestPath := shellManifestPath(p)
+	manifest, err := loadShellManifest(manifestPath)
+	switch {
+	case err == nil:
+		items = append(items, footprintItem{Kind: "manifest", Path: manifestPath, State: driftCurrent,
+			Note: fmt.Sprintf("%d entries", len(manifest.Entries))})
+	case errors.Is(err, os.ErrNotExist):
+		manifest = &shellManifest{}
+	default:
+		items = append(items, footprintItem{Kind: "manifest", Path: manifestPath, State: footprintInvalid, Note: err.Error()})
+		manifest = &shellManifest{}
+	}
+
+	for _, sh := range supportedShells {
+		var paths []string
+		if path, err := completionPath(sh, opts); err == nil {
+			paths = append(paths, path)
+		}
+		rcPaths := []string{p.rcPathFor(sh)}
+		for _, e := range manifest.Entries {
+			switch {
+			case e.Shell != sh:
+			case e.Kind == manifestKindCompletion && !slices.Contains(paths, e.Path):
+				paths = append(paths, e.Path)
+			case e.Kind == manifestKindRC && !slices.Contains(rcPaths, e.Path):
+				rcPaths = append(rcPaths, e.Path)
+			}
+		}
+		for _, path := range paths {
+			if item, ok := completionFootprint(root, sh, path, manifest.has(manifestKindCompletion, path), opts); ok {
+				items = append(items, item)
+			}
+		}
+		for _, path := range rcPaths {
+			if path == "" {
+				continue
+			}
+			if item, ok := rcFootprint(sh, path, manifest.has(manifestKindRC, path), opts); ok {
+				items = append(items, item)
+			}
+		}
+	}
+
+	if path, err := systemConfigFile(p); err == nil {
+		if item, ok := configFootprint("system_config", path); ok {
+			items = append(items, item)
+		}
+	}
+	if item, ok := configFootprint("project_config", filepath.Join(".arc", "config.yaml")); ok {
+		items = append(items, item)
+	}
+	return items
+}
+
+// completionFootprint describes the completion file at path. Files that do
+// not exist are only reported when the manifest says arc-init wrote them.
+func completionFootprint(root *cobra.Command, shell, path string, recorded bool, opts shellOptions) (footprintItem, bool) {
+	item := footprintItem{Kind: "completion", Shell: shell, Path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		item.State, item.Note = driftMissing, "recorded in the manifest but not on disk"
+		return item, recorded
+	}
+	if err != nil {
+		item.State, item.Note = footprintInvalid, err.Error()
+		return item, true
+	}
+
+	item.Version = installedVersion(data)
+	switch {
+	case recorded && shell == "fish" && filepath.Base(path) != completionFileName(shell, opts):
+		// --alias wrappers have at most a header to compare.
+		item.State, item.Note = driftCurrent, "alias wrapper"
+		if item.Version != "" && item.Version != version {
+			item.State = driftStale
+		}
+	case item.Version == "":
+		item.State, item.Note = footprintForeign, "no arc-init header; not written by arc-init"
+	default:
+		item.State, err = installedDrift(root, shell, path, data, opts)
+		if err != nil {
+			item.State, item.Note = footprintInvalid, err.Error()
+		}
+	}
+	return item, true
+}
+
+// rcFootprint describes the arc block in the RC file at path, if any.
+func rcFootprint(shell, path string, recorded bool, opts shellOptions) (footprintItem, bool) {
+	item := footprintItem{Kind: "rc_block", Shell: shell, Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		item.State, item.Note = footprintInvalid, err.Error()
+		return item, true
+	}
+	content := string(data)
+	i, j, ok := blockedit.Find(content, rcStart, rcEnd)
+	if !ok {
+		if _, _, legacy := findLegacyRCBlock(content); legacy {
+			item.State, item.Note = footprintLegacy, "old markers; arc-init shell --migrate-rc updates them"
+			return item, true
+		}
+		item.State, item.Note = driftMissing, "recorded in the manifest but the block is gone"
+		return item, recorded
+	}
+
+	// The macOS login profile holds the block that loads ~/.bashrc, not
+	// the completion block.
+	var block string
+	if isBashProfile(path, opts) {
+		block = bashProfileBlock
+	} else {
+		_, block, err = rcBlockFor(shell, opts)
+	}
+	item.State = driftStale
+	if err == nil && content[i:j] == block {
+		item.State = driftCurrent
+	}
+	return item, true
+}
+
+// configFootprint describes the config file at path, if it exists.
+func configFootprint(kind, path string) (footprintItem, bool) {
+	item := footprintItem{Kind: kind, Path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return item, false
+	}
+	if err != nil {
+		item.State, item.Note = footprintInvalid, err.Error()
+		return item, true
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		item.State, item.Note = footprintInvalid, err.Error()
+		return item, true
+	}
+	if kind == "project_config" {
+		item.State = driftCurrent
+		schema, err := loadProjectConfigSchema()
+		if err != nil {
+			return item, true
+		}
+		if problems, err := validateConfigData(data, schema); err != nil || len(problems) > 0 {
+			item.State, item.Note = footprintInvalid, "arc-init validate lists the problems"
+		}
+		return item, true
+	}
+
+	v := 1
+	if len(doc.Content) > 0 {
+		if v, err = configVersion(doc.Content[0]); err != nil {
+			item.State, item.Note = footprintInvalid, err.Error()
+			return item, true
+		}
+	}
+	item.Version = fmt.Sprintf("schema %d", v)
+	switch {
+	case v < configSchemaVersion:
+		item.State, item.Note = driftStale, "arc-init system --migrate upgrades it"
+	case v > configSchemaVersion:
+		item.State, item.Note = footprintNewer, "written by a newer arc-init"
+	default:
+		item.State = driftCurrent
+	}
+	return item, true
+}
+
+// footprintSections orders the text report.
+var footprintSections = []struct{ kind, title string }{
+	{"completion", "Completion files"},
+	{"rc_block", "RC blocks"},
+	{"manifest", "Install manifest"},
+	{"system_config", "Global config"},
+	{"project_config", "Project config"},
+}
+
+func reportFootprint(cmd *cobra.Command, items []footprintItem) {
+	out := cmd.OutOrStdout()
+	c := newColorizer(out, false)
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Arc-Init Status ===")
+	fmt.Fprintln(out)
+
+	attention := 0
+	for _, sec := range footprintSections {
+		fmt.Fprintf(out, "%s:\n", sec.title)
+		n := 0
+		for _, it := range items {
+			if it.Kind != sec.kind {
+				continue
+			}
+			n++
+			state := c.green(strings.ToUpper(it.State))
+			if it.State != driftCurrent {
+				state = c.yellow(strings.ToUpper(it.State))
+				attention++
+			}
+			var details []string
+			if it.Version != "" {
+				details = append(details, it.Version)
+			}
+			if it.Note != "" {
+				details = append(details, it.Note)
+			}
+			label := it.Path
+			if it.Shell != "" {
+				label = fmt.Sprintf("%-10s %s", it.Shell, it.Path)
+			}
+			if len(details) > 0 {
+				fmt.Fprintf(out, "  %s  %s (%s)\n", label, state, strings.Join(details, "; "))
+			} else {
+				fmt.Fprintf(out, "  %s  %s\n", label, state)
+			}
+		}
+		if n == 0 {
+			fmt.Fprintln(out, "  (none)")
+		}
+		fmt.Fprintln(out)
+	}
+
+	if attention == 0 {
+		fmt.Fprintf(out, "%d items, all current.\n", len(items))
+	} else {
+		fmt.Fprintf(out, "%d items, %d not current.\n", len(items), attention)
+	}
+}
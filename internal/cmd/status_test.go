@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFootprintDarwinBashProfileIsCurrent(t *testing.T) {
+	home := t.TempDir()
+	for _, name := range []string{".bashrc", ".bash_profile"} {
+		if err := os.WriteFile(filepath.Join(home, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paths := pathContext{goos: "darwin", home: home, configHome: filepath.Join(home, ".config"), zdotdir: home}
+	opts := shellOptions{writeRC: true, paths: paths}
+
+	status := installShell(io.Discard, NewRootCmd(), "bash", opts)
+	if len(status.errs) > 0 {
+		t.Fatalf("install: %v", status.errs)
+	}
+	if err := updateShellManifest([]shellStatus{status}, paths); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{filepath.Join(home, ".bashrc"): true, filepath.Join(home, ".bash_profile"): true}
+	for _, item := range collectFootprint(NewRootCmd(), paths) {
+		if item.Kind != "rc_block" {
+			continue
+		}
+		if !want[item.Path] {
+			t.Errorf("unexpected RC block item %+v", item)
+			continue
+		}
+		delete(want, item.Path)
+		if item.State != driftCurrent {
+			t.Errorf("%s: state = %q (%s), want %q", item.Path, item.State, item.Note, driftCurrent)
+		}
+	}
+	for path := range want {
+		t.Errorf("no RC block reported for %s", path)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// shellSummaryJSON is the --summary-file form of the --json report, which
+// adds when and by which build the run was made.
+type shellSummaryJSON struct {
+	Version     string            `json:"version"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	Shells      []shellStatusJSON `json:"shells"`
+}
+
+// writeSummaryFile saves the status report for statuses to path, as JSON
+// with --json and as the uncolored text report otherwise. The file is
+// replaced atomically, so an audit step never reads half a report.
+func writeSummaryFile(cmd *cobra.Command, path string, statuses []shellStatus, opts shellOptions) error {
+	var buf bytes.Buffer
+	now := time.Now().UTC()
+	if opts.jsonOutput {
+		summary := shellSummaryJSON{Version: version, GeneratedAt: now, Shells: make([]shellStatusJSON, 0, len(statuses))}
+		for _, s := range statuses {
+			summary.Shells = append(summary.Shells, s.toJSON())
+		}
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(summary); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(&buf, "arc-init %s, %s\n", version, now.Format(time.RFC3339))
+		out := cmd.OutOrStdout()
+		cmd.SetOut(&buf)
+		// The saved report is a full record even when stdout is filtered.
+		opts.noColor, opts.onlyChanges = true, false
+		reportShellStatus(cmd, statuses, opts)
+		cmd.SetOut(out)
+	}
+
+	if err := mkdirAll(filepath.Dir(path), opts.logger()); err != nil {
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+	if err := writeCompletionFile(path, buf.Bytes(), opts.logger()); err != nil {
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+	opts.logger().Debug("wrote summary file", "path", path, "bytes", buf.Len())
+	return nil
+}
@@ -23,6 +23,10 @@ type systemStatus struct {
 	configPath       string
 	templatesPath    string
 	reason           string
+	configMigrated   bool
+	schemaFrom       int
+	migrations       []string
+	backupPath       string
 }
 
 func newSystemCmd() *cobra.Command {
@@ -31,6 +35,8 @@ func newSystemCmd() *cobra.Command {
 		scaffold       bool
 		force          bool
 		templateSrcDir string
+		printPaths     bool
+		migrate        bool
 	)
 
 	cmd := &cobra.Command{
@@ -45,9 +51,17 @@ with message templates. Configuration is created in:
   ~/.config/arc/discord.yaml     - Discord-specific settings
   ~/.config/arc/templates/       - Discord message templates
 
+$XDG_CONFIG_HOME/arc/ is used instead of ~/.config/arc/ when XDG_CONFIG_HOME
+is set, and <dir>/arc/ when the global --config-home flag is given. Use --print to show the resolved paths without creating anything.
+
 Idempotent: Running multiple times is safe. Existing files are not overwritten
 unless --force is used.
 
+--migrate upgrades a config.yaml written for an older config layout. The
+layout is identified by its top-level version key (files without one are
+schema 1); the original is backed up next to it before being rewritten, and
+a config that is already current is left alone.
+
 Configuration search order:
   1. Project-local: .arc/config.yaml (searched up from current directory)
   2. Global: ~/.config/arc/config.yaml
@@ -56,8 +70,30 @@ Configuration search order:
 		Example: `  arc-init system --interactive
   arc-init system --scaffold
   arc-init system --interactive --template-src /path/to/templates
-  arc-init system --force`,
+  arc-init system --force
+  arc-init system --migrate
+  arc-init system --print`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if printPaths {
+				return printSystemPaths(cmd)
+			}
+
+			if migrate {
+				if scaffold || interactive || force {
+					return fmt.Errorf("--migrate cannot be combined with --scaffold, --interactive, or --force")
+				}
+				path, err := systemConfigFile(pathsFrom(cmd.Context()))
+				if err != nil {
+					return err
+				}
+				var status systemStatus
+				if err := migrateSystemConfig(path, &status, loggerFrom(cmd.Context())); err != nil {
+					return err
+				}
+				reportSystemMigration(cmd, status)
+				return nil
+			}
+
 			if scaffold && interactive {
 				return fmt.Errorf("cannot use both --scaffold and --interactive")
 			}
@@ -68,11 +104,11 @@ Configuration search order:
 
 			var status systemStatus
 			if interactive {
-				if err := runSystemInteractive(force, templateSrcDir, &status); err != nil {
+				if err := runSystemInteractive(pathsFrom(cmd.Context()), force, templateSrcDir, &status); err != nil {
 					return err
 				}
 			} else {
-				if err := runSystemScaffold(force, templateSrcDir, &status); err != nil {
+				if err := runSystemScaffold(pathsFrom(cmd.Context()), force, templateSrcDir, &status); err != nil {
 					return err
 				}
 			}
@@ -86,17 +122,34 @@ Configuration search order:
 	cmd.Flags().BoolVar(&scaffold, "scaffold", false, "Create config scaffold only (user edits manually)")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force overwrite existing config")
 	cmd.Flags().StringVar(&templateSrcDir, "template-src", "", "Source directory for Discord templates")
+	cmd.Flags().BoolVar(&migrate, "migrate", false, "Upgrade config.yaml from an older config layout, backing up the original")
+	cmd.Flags().BoolVar(&printPaths, "print", false, "Print the resolved config directory and file without writing anything")
 
 	return cmd
 }
 
-func runSystemInteractive(force bool, templateSrcDir string, status *systemStatus) error {
-	home, err := os.UserHomeDir()
+func printSystemPaths(cmd *cobra.Command) error {
+	configDir, err := pathsFrom(cmd.Context()).systemConfigDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	state := "not found"
+	if _, err := os.Stat(configFile); err == nil {
+		state = "exists"
 	}
 
-	configDir := filepath.Join(home, ".config", "arc")
+	fmt.Fprintf(cmd.OutOrStdout(), "Config directory: %s\n", configDir)
+	fmt.Fprintf(cmd.OutOrStdout(), "Config file: %s (%s)\n", configFile, state)
+	return nil
+}
+
+func runSystemInteractive(paths pathContext, force bool, templateSrcDir string, status *systemStatus) error {
+	configDir, err := paths.systemConfigDir()
+	if err != nil {
+		return err
+	}
 	templatesDir := filepath.Join(configDir, "templates")
 	configFile := filepath.Join(configDir, "config.yaml")
 
@@ -151,6 +204,8 @@ func runSystemInteractive(force bool, templateSrcDir string, status *systemStatu
 # Generated by: arc init system
 # Templates are located in: %s
 
+version: %d
+
 research_root: "%s"
 external_root: "%s"
 
@@ -174,7 +229,7 @@ discord:
   webhooks: {}
   default_webhook: ""
 `,
-		templatesDir, researchRoot, externalRoot)
+		templatesDir, configSchemaVersion, researchRoot, externalRoot)
 
 	if err := os.WriteFile(configFile, []byte(config), 0o644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
@@ -184,13 +239,11 @@ discord:
 	return nil
 }
 
-func runSystemScaffold(force bool, templateSrcDir string, status *systemStatus) error {
-	home, err := os.UserHomeDir()
+func runSystemScaffold(paths pathContext, force bool, templateSrcDir string, status *systemStatus) error {
+	configDir, err := paths.systemConfigDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
-
-	configDir := filepath.Join(home, ".config", "arc")
 	templatesDir := filepath.Join(configDir, "templates")
 	configFile := filepath.Join(configDir, "config.yaml")
 
@@ -218,6 +271,8 @@ func runSystemScaffold(force bool, templateSrcDir string, status *systemStatus)
 # Uncomment and customize the settings below.
 # Templates are located in: %s
 
+version: %d
+
 # research_root: ~/arc-engineering/docs/research-external
 # external_root: ~/arc-engineering/external
 
@@ -240,7 +295,7 @@ func runSystemScaffold(force bool, templateSrcDir string, status *systemStatus)
 #   bot_token: ""
 #   webhooks: {}
 #   default_webhook: ""
-`, templatesDir)
+`, templatesDir, configSchemaVersion)
 
 	if err := os.WriteFile(configFile, []byte(scaffold), 0o644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
@@ -284,6 +339,24 @@ func reportSystemStatus(cmd *cobra.Command, status systemStatus) {
 	}
 }
 
+func reportSystemMigration(cmd *cobra.Command, status systemStatus) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== System Configuration Migration ===")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Config file: %s\n\n", status.configPath)
+
+	if status.configUnchanged {
+		fmt.Fprintf(out, "CONFIG - UNCHANGED (%s)\n", status.reason)
+		return
+	}
+	fmt.Fprintf(out, "CONFIG - MIGRATED (schema %d -> %d)\n", status.schemaFrom, configSchemaVersion)
+	for _, m := range status.migrations {
+		fmt.Fprintf(out, "  - %s\n", m)
+	}
+	fmt.Fprintf(out, "  Backup: %s\n", status.backupPath)
+}
+
 func setupTemplatesWithStatus(destDir, srcDir string, force bool, status *systemStatus) error {
 	if err := os.MkdirAll(destDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create templates directory: %w", err)
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// defaultCommandTimeout bounds every external command arc-init runs to
+// validate scripts or detect the shell, so a shell with a slow or hanging
+// startup cannot stall an install. shell --timeout overrides it through
+// shellOptions.timeout.
+const defaultCommandTimeout = 5 * time.Second
+
+// errCheckTimeout reports a syntax check that did not finish in time.
+var errCheckTimeout = errors.New("syntax check timed out")
+
+// psParseScript parses the file named by $args[0] with the PowerShell parser
+// and exits non-zero, printing each error, if it does not parse.
+const psParseScript = `$errs = $null; ` +
+	`[void][System.Management.Automation.Language.Parser]::ParseFile($args[0], [ref]$null, [ref]$errs); ` +
+	`if ($errs.Count -gt 0) { $errs | ForEach-Object { $_.Message }; exit 1 }`
+
+// syntaxCheckCommand returns the command that checks the syntax of the script
+// at path for shell, or nil when the shell binary is not installed.
+// overrides are the --shell-path executables.
+func syntaxCheckCommand(ctx context.Context, shell, path string, overrides map[string]string) *exec.Cmd {
+	switch shell {
+	case "bash", "zsh":
+		if bin := shellBinary(shell, overrides); bin != "" {
+			return exec.CommandContext(ctx, bin, "-n", path)
+		}
+	case "fish":
+		if bin := shellBinary(shell, overrides); bin != "" {
+			return exec.CommandContext(ctx, bin, "--no-execute", path)
+		}
+	case "powershell":
+		if bin := shellBinary(shell, overrides); bin != "" {
+			return exec.CommandContext(ctx, bin, "-NoProfile", "-NonInteractive", "-Command", psParseScript, path)
+		}
+	case "tcsh":
+		if bin := shellBinary(shell, overrides); bin != "" {
+			return exec.CommandContext(ctx, bin, "-f", "-n", path)
+		}
+	case "xonsh":
+		// The xonsh wrapper is plain Python, so the Python parser suffices.
+		if bin, err := exec.LookPath("python3"); err == nil {
+			return exec.CommandContext(ctx, bin, "-c", "import ast, sys; ast.parse(open(sys.argv[1]).read(), sys.argv[1])", path)
+		}
+	}
+	return nil
+}
+
+// validateCompletion runs the shell's own syntax checker over script. It
+// returns false without an error when the shell is not installed and the
+// check had to be skipped, and false with errCheckTimeout when the checker
+// ran longer than --timeout.
+func validateCompletion(shell string, script []byte, opts shellOptions) (bool, error) {
+	f, err := os.CreateTemp("", "arc-completion-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(script); err != nil {
+		f.Close()
+		return false, err
+	}
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.commandTimeout())
+	defer cancel()
+	c := syntaxCheckCommand(ctx, shell, f.Name(), opts.shellPaths)
+	if c == nil {
+		return false, nil
+	}
+	// Children of a killed checker may keep its output pipe open; stop
+	// waiting for them shortly after the deadline.
+	c.WaitDelay = 100 * time.Millisecond
+
+	out, err := c.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, errCheckTimeout
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			msg := strings.TrimSpace(string(out))
+			if msg == "" {
+				msg = exitErr.Error()
+			}
+			return true, fmt.Errorf("%s", msg)
+		}
+		return false, err
+	}
+	return true, nil
+}
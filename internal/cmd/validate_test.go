@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubShell puts an executable named name that runs body first on PATH.
+func stubShell(t *testing.T, name, body string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("stub shells are sh scripts")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestShellTimeoutBoundsExternalCommands(t *testing.T) {
+	stubShell(t, "bash", "sleep 10")
+	home := t.TempDir()
+	opts := shellOptions{
+		force:   true,
+		timeout: 100 * time.Millisecond,
+		paths:   pathContext{goos: "linux", home: home, configHome: filepath.Join(home, ".config"), zdotdir: home},
+	}
+
+	start := time.Now()
+	var status shellStatus
+	if err := writeShellCompletion(&status, NewRootCmd(), "bash", opts); err != nil {
+		t.Fatal(err)
+	}
+	if want := "skipped (bash syntax checker timed out after 100ms)"; status.validation != want {
+		t.Errorf("validation = %q, want %q", status.validation, want)
+	}
+
+	err := runSelfTest(&status, "bash", opts)
+	if !errors.Is(err, ErrSelfTest) || !strings.Contains(err.Error(), "no result after 100ms") {
+		t.Errorf("runSelfTest error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("took %s; --timeout was not applied", elapsed)
+	}
+}
+
+func TestValidateCompletionUsesShellPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub shells are sh scripts")
+	}
+	stub := filepath.Join(t.TempDir(), "bash5")
+	if err := os.WriteFile(stub, []byte("#!/bin/sh\necho 'checked by stub' >&2\nexit 2\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := parseShellPaths([]string{"bash=" + stub})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checked, err := validateCompletion("bash", []byte("complete -F _arc arc\n"), shellOptions{shellPaths: paths})
+	if !checked || err == nil || err.Error() != "checked by stub" {
+		t.Errorf("validateCompletion = %v, %v; want the stub's failure", checked, err)
+	}
+}
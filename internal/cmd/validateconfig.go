@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultProjectConfig is the project config arc-init project writes.
+var defaultProjectConfig = filepath.Join(".arc", "config.yaml")
+
+func newValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [FILE]",
+		Short: "Check a project config against the arc config schema",
+		Long: `Check a project config (default .arc/config.yaml) against the JSON Schema
+embedded in this binary.
+
+Unknown keys, values of the wrong type or range, and missing required keys are
+reported with their line and column. Exits non-zero when the config is
+invalid, so it can run in CI.`,
+		Example: `  arc-init validate
+  arc-init validate path/to/.arc/config.yaml`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := defaultProjectConfig
+			if len(args) == 1 {
+				path = args[0]
+			}
+			cmd.SilenceUsage = true
+			return validateProjectConfig(cmd, path)
+		},
+	}
+}
+
+// validateProjectConfig reports every schema problem in the config at path
+// and fails when there is at least one.
+func validateProjectConfig(cmd *cobra.Command, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	schema, err := loadProjectConfigSchema()
+	if err != nil {
+		return err
+	}
+	problems, err := validateConfigData(data, schema)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: valid\n", path)
+		return nil
+	}
+	for _, p := range problems {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s:%s\n", path, p)
+	}
+	return fmt.Errorf("%s: %d schema problem(s)", path, len(problems))
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Build metadata, injected at link time:
+//
+//	go build -ldflags "-X github.com/yourorg/arc-init/internal/cmd.version=v1.2.3 \
+//	  -X github.com/yourorg/arc-init/internal/cmd.commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/yourorg/arc-init/internal/cmd.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+}
+
+func newVersionCmd() *cobra.Command {
+	var short, jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print arc-init build information",
+		Long: `Print the arc-init version, git commit, and build date.
+
+Every generated completion file records the version that wrote it, so this is
+the version doctor and shell --check compare installed files against.`,
+		Example: `  arc-init version
+  arc-init version --short
+  arc-init version --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			switch {
+			case jsonOutput:
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(versionInfo{Version: version, Commit: commit, BuildDate: buildDate})
+			case short:
+				fmt.Fprintln(out, version)
+			default:
+				fmt.Fprintf(out, "arc-init %s\n", version)
+				fmt.Fprintf(out, "  Commit: %s\n", commit)
+				fmt.Fprintf(out, "  Built:  %s\n", buildDate)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&short, "short", false, "Print only the version")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print build information as JSON")
+
+	return cmd
+}
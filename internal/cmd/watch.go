@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// watchPoll is how often --watch checks the binary, and watchSettle how long
+// it must stay unchanged before completions are regenerated, so a build that
+// writes the binary in several steps triggers one regeneration.
+const (
+	watchPoll   = 500 * time.Millisecond
+	watchSettle = time.Second
+)
+
+// binaryStamp identifies one build of the watched binary.
+type binaryStamp struct {
+	mod  time.Time
+	size int64
+}
+
+func statBinary(path string) (binaryStamp, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return binaryStamp{}, false
+	}
+	return binaryStamp{info.ModTime(), info.Size()}, true
+}
+
+// watchBinary regenerates completions each time the running binary is
+// rebuilt, until interrupted. The command tree must come from the rebuilt
+// binary, not this process, so each regeneration re-runs it with the same
+// arguments minus --watch, plus --refresh and --force to replace the files
+// the previous build wrote.
+func watchBinary(cmd *cobra.Command, opts shellOptions) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the running binary to watch: %w", err)
+	}
+	var args []string
+	for _, a := range os.Args[1:] {
+		if a != "--watch" && !strings.HasPrefix(a, "--watch=") {
+			args = append(args, a)
+		}
+	}
+	args = append(args, "--refresh", "--force")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	regenerate := func() {
+		fmt.Fprintf(out, "[%s] regenerating completions from %s\n", time.Now().Format(time.TimeOnly), exe)
+		c := exec.CommandContext(ctx, exe, args...)
+		c.Stdout, c.Stderr = out, cmd.ErrOrStderr()
+		if err := c.Run(); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "regeneration failed: %v\n", err)
+		}
+	}
+
+	last, _ := statBinary(exe)
+	regenerate()
+	fmt.Fprintf(out, "Watching %s for rebuilds (Ctrl-C to stop)\n", exe)
+
+	ticker := time.NewTicker(watchPoll)
+	defer ticker.Stop()
+	var pending binaryStamp
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(out, "Stopped watching.")
+			return nil
+		case <-ticker.C:
+		}
+		cur, ok := statBinary(exe)
+		if !ok {
+			// Mid-rebuild the binary may briefly not exist.
+			continue
+		}
+		if cur != pending {
+			pending, changedAt = cur, time.Now()
+			opts.logger().Debug("watched binary changed", "path", exe, "mod", cur.mod, "size", cur.size)
+			continue
+		}
+		if cur != last && time.Since(changedAt) >= watchSettle {
+			last = cur
+			regenerate()
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// Cobra cannot generate nushell, elvish, xonsh, or tcsh completions, so these
+// wrappers ask the binary itself for candidates via the hidden __complete
+// command. Its output is one "value<TAB>description" candidate per line
+// followed by a ":<directive>" line, which the wrappers drop.
+
+var nushellTemplate = template.Must(template.New("nushell").Parse(`# {{.Name}} completions for nushell
+# Generated by arc-init. Load with: source arc.nu (or add it to config.nu)
+
+def "nu-complete {{.Name}}" [context: string] {
+    let args = ($context | split row " " | skip 1)
+    ^{{.Name}} __complete ...$args
+    | complete
+    | get stdout
+    | lines
+    | where {|line| not ($line | str starts-with ":") }
+    | each {|line|
+        let parts = ($line | split row "\t")
+        if ($parts | length) > 1 {
+            { value: ($parts | get 0), description: ($parts | get 1) }
+        } else {
+            { value: ($parts | get 0) }
+        }
+    }
+}
+
+export extern "{{.Name}}" [...args: string@"nu-complete {{.Name}}"]
+`))
+
+var elvishTemplate = template.Must(template.New("elvish").Parse(`# {{.Name}} completions for elvish
+# Generated by arc-init. Load with: use arc (in rc.elv)
+
+use str
+
+set edit:completion:arg-completer[{{.Name}}] = {|@words|
+    var args = $words[1..]
+    {{.Name}} __complete $@args 2>/dev/null | from-lines | each {|line|
+        if (str:has-prefix $line ":") {
+            continue
+        }
+        var parts = [(str:split "\t" $line)]
+        if (> (count $parts) 1) {
+            edit:complex-candidate $parts[0] &display=$parts[0]" - "$parts[1]
+        } else {
+            edit:complex-candidate $parts[0]
+        }
+    }
+}
+`))
+
+// xonshTemplate is a plain Python module, so it can be sourced from rc.xsh
+// and checked with the Python parser. _arc_commands holds the command names
+// it completes; --alias adds to it.
+var xonshTemplate = template.Must(template.New("xonsh").Parse(`# {{.Name}} completions for xonsh
+# Generated by arc-init. Load with: source arc.py (in rc.xsh)
+
+import subprocess
+
+from xonsh.completers.completer import add_one_completer
+from xonsh.completers.tools import RichCompletion, contextual_command_completer
+
+_arc_commands = {"{{.Name}}"}
+
+
+@contextual_command_completer
+def _arc_completer(context):
+    if context.command not in _arc_commands:
+        return None
+    args = [arg.value for arg in context.args[1 : context.arg_index]]
+    try:
+        out = subprocess.run(
+            ["{{.Name}}", "__complete", *args, context.prefix],
+            capture_output=True,
+            text=True,
+        ).stdout
+    except OSError:
+        return None
+    candidates = set()
+    for line in out.splitlines():
+        if not line or line.startswith(":"):
+            continue
+        value, _, description = line.partition("\t")
+        candidates.add(RichCompletion(value, description=description))
+    return candidates
+
+
+add_one_completer("{{.Name}}", _arc_completer, "start")
+`))
+
+func genNushellCompletion(root *cobra.Command, w io.Writer) error {
+	return nushellTemplate.Execute(w, struct{ Name string }{root.Name()})
+}
+
+func genElvishCompletion(root *cobra.Command, w io.Writer) error {
+	return elvishTemplate.Execute(w, struct{ Name string }{root.Name()})
+}
+
+func genXonshCompletion(root *cobra.Command, w io.Writer) error {
+	return xonshTemplate.Execute(w, struct{ Name string }{root.Name()})
+}
+
+func genTcshCompletion(root *cobra.Command, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# %s completions for tcsh\n# Generated by arc-init. Load with: source arc.tcsh (in ~/.tcshrc)\n\n%s\n",
+		root.Name(), tcshCompleteLine(root.Name(), root.Name()))
+	return err
+}
+
+// tcshCompleteLine returns the complete rule that makes name complete like
+// command. tcsh exports the line being completed as $COMMAND_LINE; csh
+// quoting cannot split it safely, so the rule hands it to sh, which drops the
+// command word, adds an empty word after a trailing space, and keeps only the
+// candidate values.
+func tcshCompleteLine(name, command string) string {
+	script := `set -f; set -- $COMMAND_LINE; shift; case "$COMMAND_LINE" in *" ") set -- "$@" "";; esac; ` +
+		command + ` __complete "$@" 2>/dev/null | grep -v "^:" | cut -f1`
+	return "complete " + name + " 'p,*,`sh -c '\\''" + script + "'\\''`,'"
+}
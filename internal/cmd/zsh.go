@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// zsh RC strategies, reported in the shell status.
+const (
+	zshVanilla = "vanilla"
+	zshOhMyZsh = "oh-my-zsh"
+	zshPrezto  = "prezto"
+)
+
+// detectZshFrameworks returns the oh-my-zsh and prezto install directories,
+// or "" for each that is not present. oh-my-zsh is found via $ZSH or
+// ~/.oh-my-zsh, prezto via ${ZDOTDIR:-$HOME}/.zprezto.
+func detectZshFrameworks(home, zdotdir string) (ohMyZsh, prezto string) {
+	candidates := []string{os.Getenv("ZSH")}
+	if home != "" {
+		candidates = append(candidates, filepath.Join(home, ".oh-my-zsh"))
+	}
+	for _, dir := range candidates {
+		if isDir(dir) {
+			ohMyZsh = dir
+			break
+		}
+	}
+	if zdotdir != "" && isDir(filepath.Join(zdotdir, ".zprezto")) {
+		prezto = filepath.Join(zdotdir, ".zprezto")
+	}
+	return ohMyZsh, prezto
+}
+
+func isDir(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// zshFramework reports which zsh framework manages compinit.
+func (p pathContext) zshFramework() string {
+	switch {
+	case p.ohMyZsh != "":
+		return zshOhMyZsh
+	case p.prezto != "":
+		return zshPrezto
+	}
+	return zshVanilla
+}
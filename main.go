@@ -4,6 +4,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -14,6 +15,11 @@ func main() {
 	root := cmd.NewRootCmd()
 	if err := root.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "arc-init: %v\n", err)
-		os.Exit(1)
+		code := cmd.ExitFailure
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.Code
+		}
+		os.Exit(code)
 	}
 }